@@ -0,0 +1,121 @@
+package game
+
+import (
+	"errors"
+
+	"github.com/RGood/game_engine/pkg/gamestate"
+)
+
+var (
+	ErrNoAbility       = errors.New("general has no ability")
+	ErrAbilityCooldown = errors.New("ability is on cooldown")
+)
+
+// GeneralAbility is a general's Bloodborn spell. It is cast from outside the
+// hand and, once used, is unavailable until Cooldown of its owner's turns have
+// started.
+type GeneralAbility struct {
+	Name      string
+	Cost      int
+	Cooldown  int
+	Targeting TargetSpec
+	Effect    func(*Player, *gamestate.Gamestate, []Unit, []Position) `json:"-"`
+}
+
+func NewGeneralAbility(name string, cost int, cooldown int, targeting TargetSpec, effect func(*Player, *gamestate.Gamestate, []Unit, []Position)) *GeneralAbility {
+	return &GeneralAbility{
+		Name:      name,
+		Cost:      cost,
+		Cooldown:  cooldown,
+		Targeting: targeting,
+		Effect:    effect,
+	}
+}
+
+func (ability *GeneralAbility) GetCost() int {
+	return ability.Cost
+}
+
+func AbilityCards(card Card) bool {
+	_, ok := card.(*GeneralAbility)
+	return ok
+}
+
+// AbilityCooldown is how many more of the player's turns must start before
+// their ability can be used again.
+func (p *Player) AbilityCooldown() int {
+	return p.abilityCooldown
+}
+
+func (p *Player) tickAbilityCooldown() {
+	if p.abilityCooldown > 0 {
+		p.abilityCooldown--
+	}
+}
+
+// UseAbility casts the player's general ability. Like a spell, it is checked
+// and paid for before anything resolves, and the cooldown starts immediately.
+func (p *Player) UseAbility(gs *gamestate.Gamestate, units []Unit, positions []Position) error {
+	if p.Ability == nil {
+		return ErrNoAbility
+	}
+
+	if err := p.canPlay(gs); err != nil {
+		return err
+	}
+
+	if p.abilityCooldown > 0 {
+		return ErrAbilityCooldown
+	}
+
+	if err := p.Ability.Targeting.Check(p, units, positions); err != nil {
+		return err
+	}
+
+	cost, ok := p.payForCard(p.Ability)
+	if !ok {
+		return ErrInsufficientMana
+	}
+	p.abilityCooldown = p.Ability.Cooldown
+
+	gs.MakeMove(&AbilityAction{
+		Owner:   p,
+		Ability: p.Ability,
+		Cost:    cost,
+		Units:   units,
+		Tiles:   positions,
+	})
+
+	return nil
+}
+
+// AbilityAction resolves a general ability. Like SpellAction, it fizzles if
+// its targets are no longer valid.
+type AbilityAction struct {
+	Owner   *Player
+	Ability *GeneralAbility
+	Cost    int
+	Units   []Unit
+	Tiles   []Position
+}
+
+func (aa *AbilityAction) Execute(gs *gamestate.Gamestate) *gamestate.Gamestate {
+	if aa.Ability.Targeting.Check(aa.Owner, aa.Units, aa.Tiles) != nil {
+		return gs
+	}
+
+	aa.Ability.Effect(aa.Owner, gs, aa.Units, aa.Tiles)
+
+	return gs
+}
+
+func (aa *AbilityAction) AffectedUnits() []Unit {
+	return aa.Units
+}
+
+// Refund returns the mana paid for a countered ability and makes it ready
+// again.
+func (aa *AbilityAction) Refund(gs *gamestate.Gamestate) {
+	aa.Owner.GainMana(aa.Cost)
+	aa.Owner.abilityCooldown = 0
+}
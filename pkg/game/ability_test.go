@@ -0,0 +1,51 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/RGood/game_engine/pkg/gamestate"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_generalAbility(t *testing.T) {
+	board := NewUnitBoard(9, 5)
+	roar := NewGeneralAbility("Roar", 1, 2, TargetSpec{Kind: TargetAnyUnit}, func(owner *Player, gs *gamestate.Gamestate, units []Unit, _ []Position) {
+		gs.QueueAction(&EffectAction{
+			Unit: units[0],
+			Effect: func(u Unit) {
+				u.BuffAttack(2)
+			},
+		})
+	})
+	p1 := NewPlayerWithGeneral("Foo", GeneralDefinition{Name: "Argeon", Health: 25, Attack: 2, Ability: roar}, board, NewPosition(0, 2), true)
+	p2 := NewPlayer("Bar", "Songhai", board, NewPosition(8, 2), false)
+	p1.Mana = 2
+	gs := gamestate.NewGamestate(p1, p2)
+	p1general := p1.GetGeneral()
+
+	assert.ErrorIs(t, p2.UseAbility(gs, []Unit{p1general}, nil), ErrNoAbility)
+	assert.ErrorIs(t, p1.UseAbility(gs, nil, nil), ErrInvalidTargets)
+
+	assert.NoError(t, p1.UseAbility(gs, []Unit{p1general}, nil))
+	assert.Equal(t, 4, p1general.GetAttack())
+	assert.Equal(t, 1, p1.Mana)
+	assert.Equal(t, 2, p1.AbilityCooldown())
+	assert.ErrorIs(t, p1.UseAbility(gs, []Unit{p1general}, nil), ErrAbilityCooldown)
+
+	// The cooldown counts down as the owner's turns start
+	gs.MakeMove(&EndTurnAction{Owner: p1})
+	assert.Equal(t, 2, p1.AbilityCooldown())
+	assert.ErrorIs(t, p1.UseAbility(gs, []Unit{p1general}, nil), ErrNotYourTurn)
+	gs.MakeMove(&EndTurnAction{Owner: p2})
+	assert.Equal(t, 1, p1.AbilityCooldown())
+	gs.MakeMove(&EndTurnAction{Owner: p1})
+	gs.MakeMove(&EndTurnAction{Owner: p2})
+	assert.Equal(t, 0, p1.AbilityCooldown())
+
+	// Cost modifiers apply to abilities too
+	p1.Mana = 0
+	assert.ErrorIs(t, p1.UseAbility(gs, []Unit{p1general}, nil), ErrInsufficientMana)
+	p1.AddCostModifier(&CostModifier{Filter: AbilityCards, Value: -1, Uses: 1})
+	assert.NoError(t, p1.UseAbility(gs, []Unit{p1general}, nil))
+	assert.Equal(t, 6, p1general.GetAttack())
+}
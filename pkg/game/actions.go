@@ -8,11 +8,76 @@ type MoveAction struct {
 }
 
 func (ma *MoveAction) Execute(gs *gamestate.Gamestate) *gamestate.Gamestate {
+	command := isCommand(gs)
+	if command && (!canAct(gs, ma.Unit) || !ma.Unit.CanMove()) {
+		return gs
+	}
+
+	// Provoked units are held in place
+	if board := ma.Unit.GetBoard(); board == nil || board.IsProvoked(ma.Unit) {
+		return gs
+	}
+
+	from := ma.Unit.GetPosition()
 	ma.Unit.Move(ma.Position)
+	if command {
+		ma.Unit.SpendMove()
+	}
+
+	// Units turn to face the way they walked; purely vertical moves keep
+	// their facing.
+	if to := ma.Unit.GetPosition(); to.X != from.X {
+		queueFacing(gs, ma.Unit, to.X > from.X)
+	}
+
+	return gs
+}
+
+// isCommand reports whether the action resolving now was made by a player
+// rather than queued by a spell or trigger. Only player commands are held to
+// the turn and to the unit's move and attack budget.
+func isCommand(gs *gamestate.Gamestate) bool {
+	current := gs.Current()
+	return current == nil || current.IsRoot()
+}
+
+// canAct reports whether it is the unit's owner's turn. Moves and attacks
+// commanded for other units are ignored.
+func canAct(gs *gamestate.Gamestate, unit Unit) bool {
+	owner := unit.GetOwner()
+	return owner != nil && owner.isActive(gs)
+}
+
+func (ma *MoveAction) AffectedUnits() []Unit {
+	return []Unit{ma.Unit}
+}
+
+// FaceAction turns a unit left or right. Moves and attacks queue one whenever
+// they turn a unit, so listeners can follow facing changes.
+type FaceAction struct {
+	Unit  Unit
+	Right bool
+}
+
+func (fa *FaceAction) Execute(gs *gamestate.Gamestate) *gamestate.Gamestate {
+	fa.Unit.SetFacing(fa.Right)
 
 	return gs
 }
 
+func (fa *FaceAction) AffectedUnits() []Unit {
+	return []Unit{fa.Unit}
+}
+
+func queueFacing(gs *gamestate.Gamestate, unit Unit, right bool) {
+	if unit.FacesRight() != right {
+		gs.QueueAction(&FaceAction{
+			Unit:  unit,
+			Right: right,
+		})
+	}
+}
+
 type PlaceUnitAction struct {
 	Owner    *Player
 	Board    *UnitBoard
@@ -21,12 +86,23 @@ type PlaceUnitAction struct {
 }
 
 func (ma *PlaceUnitAction) Execute(gs *gamestate.Gamestate) *gamestate.Gamestate {
-	ma.Unit.Place(ma.Owner, ma.Position)
-	ma.Unit.Subscribe(gs)
+	putIntoPlay(gs, ma.Owner, ma.Unit, ma.Position)
 
 	return gs
 }
 
+// putIntoPlay places a unit that has just been played or summoned.
+func putIntoPlay(gs *gamestate.Gamestate, owner *Player, unit Unit, position Position) {
+	unit.Place(owner, position)
+	unit.Exhaust()
+	onSummon(unit)
+	unit.Subscribe(gs)
+}
+
+func (ma *PlaceUnitAction) AffectedUnits() []Unit {
+	return []Unit{ma.Unit}
+}
+
 type RemoveUnitAction struct {
 	Unit Unit
 }
@@ -38,6 +114,10 @@ func (ra *RemoveUnitAction) Execute(gs *gamestate.Gamestate) *gamestate.Gamestat
 	return gs
 }
 
+func (ra *RemoveUnitAction) AffectedUnits() []Unit {
+	return []Unit{ra.Unit}
+}
+
 type DamageAction struct {
 	Unit   Unit
 	Damage int
@@ -54,6 +134,10 @@ func (da *DamageAction) Execute(gs *gamestate.Gamestate) *gamestate.Gamestate {
 	return gs
 }
 
+func (da *DamageAction) AffectedUnits() []Unit {
+	return []Unit{da.Unit}
+}
+
 type HealAction struct {
 	Unit Unit
 	Heal int
@@ -65,61 +149,147 @@ func (ha *HealAction) Execute(gs *gamestate.Gamestate) *gamestate.Gamestate {
 	return gs
 }
 
+func (ha *HealAction) AffectedUnits() []Unit {
+	return []Unit{ha.Unit}
+}
+
 type AttackAction struct {
 	Attacker Unit
 	Defender Unit
 }
 
 func (aa *AttackAction) Execute(gs *gamestate.Gamestate) *gamestate.Gamestate {
+	command := isCommand(gs)
+	if command && (!canAct(gs, aa.Attacker) || !aa.Attacker.CanAttack()) {
+		return gs
+	}
 
-	if aa.Attacker.InRange(aa.Defender) {
-		allUnits := []Unit{}
-		for unit, _ := range aa.Attacker.GetBoard().Units {
-			allUnits = append(allUnits, unit)
+	if board := aa.Attacker.GetBoard(); board != nil && board.IsValidTarget(aa.Attacker, aa.Defender) {
+		if command {
+			aa.Attacker.SpendAttack()
+		}
+
+		// The attacker turns towards its target
+		if xDiff := aa.Defender.GetPosition().X - aa.Attacker.GetPosition().X; xDiff != 0 {
+			queueFacing(gs, aa.Attacker, xDiff > 0)
 		}
 
 		posDiff := aa.Attacker.GetPosition().Diff(aa.Defender.GetPosition())
-		wasBackstabbed := aa.Attacker.HasAttribute("backstab") && (posDiff.Y == 0 && ((posDiff.X == -1 && aa.Defender.FacesRight()) || (posDiff.X == 1 && !aa.Defender.FacesRight())))
+		wasBackstabbed := aa.Attacker.HasAttribute(Backstab) && (posDiff.Y == 0 && ((posDiff.X == -1 && aa.Defender.FacesRight()) || (posDiff.X == 1 && !aa.Defender.FacesRight())))
 
 		collateralDamage := map[Unit]int{}
 		collateralDamage[aa.Defender] = aa.Attacker.GetAttack()
 		if wasBackstabbed {
-			collateralDamage[aa.Defender] += aa.Attacker.GetAttributeValue("backstab")
+			collateralDamage[aa.Defender] += aa.Attacker.GetAttributeValue(Backstab)
 		}
 
-		if aa.Attacker.HasAttribute("blast") {
-			isInline, inlineFunc := aa.Attacker.IsInline(aa.Defender)
-			if isInline {
-				for _, unit := range filterUnits(filterUnits(allUnits, inlineFunc), aa.Attacker.IsEnemy) {
-					collateralDamage[unit] = aa.Attacker.GetAttack()
-				}
-			}
-			// Calculate other enemy units in that line and add to collateral damage
-		}
+		// Keywords such as blast and frenzy add collateral damage
+		onAttack(aa.Attacker, aa.Defender, collateralDamage)
 
-		if aa.Attacker.HasAttribute("frenzy") && aa.Attacker.IsNear(aa.Defender) {
-			for _, unit := range filterUnits(filterUnits(allUnits, aa.Attacker.IsNear), aa.Attacker.IsEnemy) {
-				collateralDamage[unit] = aa.Attacker.GetAttack()
+		// Damage is fixed now and dealt all at once by the combat step, with
+		// the defender hit first and collateral in board order so that
+		// resolution does not depend on map iteration order.
+		combat := &CombatDamageAction{
+			Hits: []*DamageAction{{Unit: aa.Defender, Damage: collateralDamage[aa.Defender]}},
+		}
+		for _, unit := range aa.Attacker.GetBoard().SortedUnits() {
+			if damage, ok := collateralDamage[unit]; ok && unit != aa.Defender {
+				combat.Hits = append(combat.Hits, &DamageAction{Unit: unit, Damage: damage})
 			}
 		}
 
-		for unit, damage := range collateralDamage {
-			gs.QueueAction(&DamageAction{Unit: unit, Damage: damage})
+		// The defender strikes back with the attack it had when attacked
+		combat.Counterattack = &CounterattackAction{
+			Unit:        aa.Defender,
+			Target:      aa.Attacker,
+			Damage:      aa.Defender.GetAttack(),
+			Backstabbed: wasBackstabbed,
+			Combat:      combat,
 		}
 
-		// Do counter-attack check
-		// Not backstabbed and ranged or near
-		if !wasBackstabbed && (aa.Defender.HasAttribute("ranged") || aa.Defender.IsNear(aa.Attacker)) {
-			gs.QueueAction(&DamageAction{Unit: aa.Attacker, Damage: aa.Defender.GetAttack()})
-		}
+		gs.QueueAction(combat)
 	}
 
 	return gs
 }
 
+func (aa *AttackAction) AffectedUnits() []Unit {
+	return []Unit{aa.Attacker, aa.Defender}
+}
+
+// CombatDamageAction deals an attack's damage and the counterattack's as one
+// step. The counterattack resolves first, while every unit is still standing,
+// then each hit resolves as its own DamageAction so interceptors and listeners
+// see per-unit damage. Deaths are only queued, so they follow the whole step.
+type CombatDamageAction struct {
+	Hits          []*DamageAction
+	Counterattack *CounterattackAction
+}
+
+func (cda *CombatDamageAction) Execute(gs *gamestate.Gamestate) *gamestate.Gamestate {
+	if cda.Counterattack != nil {
+		gs.ResolveNow(cda.Counterattack)
+	}
+
+	for _, hit := range cda.Hits {
+		gs.ResolveNow(hit)
+	}
+
+	return gs
+}
+
+func (cda *CombatDamageAction) AffectedUnits() []Unit {
+	units := []Unit{}
+	for _, hit := range cda.Hits {
+		units = append(units, hit.Unit)
+	}
+
+	return units
+}
+
+// CounterattackAction is the defender's strike back at its attacker. During
+// an attack it resolves as part of the CombatDamageAction and adds its hit to
+// that; queued on its own it deals its damage directly. Interceptors can
+// cancel it or change its damage; eligibility is checked when it resolves.
+type CounterattackAction struct {
+	Unit        Unit
+	Target      Unit
+	Damage      int
+	Backstabbed bool
+	Combat      *CombatDamageAction `json:"-"`
+}
+
+// CanCounterattack is true when the unit is still on the board, was not
+// backstabbed, is not stunned and its range profile reaches the target.
+func (ca *CounterattackAction) CanCounterattack() bool {
+	return ca.Unit.IsAlive() && ca.Target.IsAlive() &&
+		!ca.Backstabbed &&
+		!ca.Unit.HasAttribute(Stunned) &&
+		ca.Unit.InRange(ca.Target)
+}
+
+func (ca *CounterattackAction) Execute(gs *gamestate.Gamestate) *gamestate.Gamestate {
+	if !ca.CanCounterattack() {
+		return gs
+	}
+
+	hit := &DamageAction{Unit: ca.Target, Damage: ca.Damage}
+	if ca.Combat != nil {
+		ca.Combat.Hits = append(ca.Combat.Hits, hit)
+	} else {
+		gs.QueueAction(hit)
+	}
+
+	return gs
+}
+
+func (ca *CounterattackAction) AffectedUnits() []Unit {
+	return []Unit{ca.Unit, ca.Target}
+}
+
 type EffectAction struct {
 	Unit   Unit
-	Effect func(Unit)
+	Effect func(Unit) `json:"-"`
 }
 
 func (ea *EffectAction) Execute(gs *gamestate.Gamestate) *gamestate.Gamestate {
@@ -128,6 +298,10 @@ func (ea *EffectAction) Execute(gs *gamestate.Gamestate) *gamestate.Gamestate {
 	return gs
 }
 
+func (ea *EffectAction) AffectedUnits() []Unit {
+	return []Unit{ea.Unit}
+}
+
 type DispelAction struct {
 	Unit Unit
 }
@@ -138,20 +312,88 @@ func (dispAction *DispelAction) Execute(gs *gamestate.Gamestate) *gamestate.Game
 	return gs
 }
 
+func (dispAction *DispelAction) AffectedUnits() []Unit {
+	return []Unit{dispAction.Unit}
+}
+
+// CastSpellAction announces a spell with its targets. Interceptors can cancel
+// it to counter the spell or change its targets to redirect it, and listeners
+// can copy it; the spell itself resolves in the SpellAction it queues.
+type CastSpellAction struct {
+	Owner     *Player
+	Spell     Spell
+	Cost      int
+	Targeting TargetSpec
+	Units     []Unit
+	Tiles     []Position
+}
+
+func (csa *CastSpellAction) Execute(gs *gamestate.Gamestate) *gamestate.Gamestate {
+	resolve := csa.Copy(csa.Owner)
+	resolve.Cost = csa.Cost
+	gs.QueueAction(resolve)
+
+	return gs
+}
+
+func (csa *CastSpellAction) AffectedUnits() []Unit {
+	return csa.Units
+}
+
+// Refund returns the mana paid for a spell that was countered with a refund.
+func (csa *CastSpellAction) Refund(gs *gamestate.Gamestate) {
+	csa.Owner.GainMana(csa.Cost)
+}
+
+// Copy makes a free SpellAction resolving the announced spell for another
+// player on the same targets. The targets must also be valid for the new
+// owner, or the copy fizzles.
+func (csa *CastSpellAction) Copy(owner *Player) *SpellAction {
+	return &SpellAction{
+		Owner:     owner,
+		Spell:     csa.Spell,
+		Targeting: csa.Targeting,
+		Units:     append([]Unit{}, csa.Units...),
+		Tiles:     append([]Position{}, csa.Tiles...),
+	}
+}
+
+// SpellAction resolves a cast spell. Its targets are checked again on
+// resolution, and the spell fizzles if any of them is no longer valid. The
+// spell's own effect runs on the action's targets unless Effect replaces it.
 type SpellAction struct {
-	Owner  *Player
-	Spell  Spell
-	Units  []Unit
-	Tiles  []Position
-	Effect func()
+	Owner     *Player
+	Spell     Spell
+	Cost      int
+	Targeting TargetSpec
+	Units     []Unit
+	Tiles     []Position
+	Effect    func() `json:"-"`
 }
 
 func (sp *SpellAction) Execute(gs *gamestate.Gamestate) *gamestate.Gamestate {
-	sp.Effect()
+	if sp.Targeting.Check(sp.Owner, sp.Units, sp.Tiles) != nil {
+		return gs
+	}
+
+	if sp.Effect != nil {
+		sp.Effect()
+	} else {
+		sp.Spell.Resolve(sp.Owner, gs, sp.Units, sp.Tiles)
+	}
 
 	return gs
 }
 
+func (sp *SpellAction) AffectedUnits() []Unit {
+	return sp.Units
+}
+
+// Refund returns the mana paid for a spell that was countered with a refund.
+func (sp *SpellAction) Refund(gs *gamestate.Gamestate) {
+	sp.Owner.GainMana(sp.Cost)
+}
+
 type EquipArtifactAction struct {
 	Owner    *Player
 	Artifact *Artifact
@@ -173,13 +415,85 @@ func (raa *RemoveArtifactAction) Execute(gs *gamestate.Gamestate) *gamestate.Gam
 	return gs
 }
 
+// ChangeOwnerAction gives a unit to another player (mind control). The unit
+// keeps its position, buffs and triggers, but turns to face its new owner's
+// direction and cannot act until that player's next turn.
+type ChangeOwnerAction struct {
+	Unit  Unit
+	Owner *Player
+}
+
+func (coa *ChangeOwnerAction) Execute(gs *gamestate.Gamestate) *gamestate.Gamestate {
+	if !coa.Unit.IsAlive() || coa.Unit.GetOwner() == coa.Owner || coa.Unit.GetType() == "general" {
+		return gs
+	}
+
+	// Re-register so the unit's triggers order among its new owner's units
+	coa.Unit.Unsubscribe(gs)
+	coa.Unit.SetOwner(coa.Owner)
+	coa.Unit.Exhaust()
+	coa.Unit.Subscribe(gs)
+
+	return gs
+}
+
+func (coa *ChangeOwnerAction) AffectedUnits() []Unit {
+	return []Unit{coa.Unit}
+}
+
+// TransformAction replaces a unit with a different one (polymorph) on the same
+// tile and for the same owner. Nothing carries over: the old unit's buffs and
+// triggers leave with it, it does not die, and the new unit is exhausted.
+// Generals cannot be transformed.
+type TransformAction struct {
+	Unit Unit
+	Into Unit
+}
+
+func (ta *TransformAction) Execute(gs *gamestate.Gamestate) *gamestate.Gamestate {
+	if !ta.Unit.IsAlive() || ta.Unit.GetType() == "general" {
+		return gs
+	}
+
+	owner := ta.Unit.GetOwner()
+	pos := ta.Unit.GetPosition()
+
+	ta.Unit.Remove()
+	ta.Unit.Unsubscribe(gs)
+
+	ta.Into.Place(owner, pos)
+	ta.Into.Exhaust()
+	ta.Into.Subscribe(gs)
+
+	return gs
+}
+
+func (ta *TransformAction) AffectedUnits() []Unit {
+	return []Unit{ta.Unit, ta.Into}
+}
+
 type EndTurnAction struct {
 	Owner *Player
 }
 
 func (eta *EndTurnAction) Execute(gs *gamestate.Gamestate) *gamestate.Gamestate {
 	if gs.ActivePlayer == eta.Owner {
-		return gs.EndTurn()
+		for _, player := range gs.Players {
+			if player, ok := player.(*Player); ok {
+				player.expireCostModifiers()
+			}
+		}
+
+		gs.EndTurn()
+
+		if active, ok := gs.ActivePlayer.(*Player); ok {
+			for _, unit := range active.GetUnits() {
+				unit.Refresh()
+			}
+
+			active.tickAbilityCooldown()
+			active.Board.startTurn(eta.Owner, active, gs)
+		}
 	}
 
 	return gs
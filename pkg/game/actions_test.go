@@ -15,6 +15,13 @@ func setupGamestate() (*Player, *Player, *gamestate.Gamestate) {
 	return p1, p2, gamestate.NewGamestate(p1, p2)
 }
 
+// endTurns ends each player's turn in order.
+func endTurns(gs *gamestate.Gamestate, players ...*Player) {
+	for _, player := range players {
+		gs.MakeMove(&EndTurnAction{Owner: player})
+	}
+}
+
 func Test_gameSetup(t *testing.T) {
 	p1, p2, _ := setupGamestate()
 	p1units := p1.GetUnits()
@@ -200,11 +207,15 @@ func Test_backstabAttack(t *testing.T) {
 		Position: NewPosition(1, 2),
 	})
 
+	gs.MakeMove(&EndTurnAction{Owner: p1})
+
 	gs.MakeMove(&MoveAction{
 		Unit:     p2general,
 		Position: NewPosition(0, 2),
 	})
 
+	gs.MakeMove(&EndTurnAction{Owner: p2})
+
 	gs.MakeMove(&AttackAction{
 		Attacker: p1general,
 		Defender: p2general,
@@ -227,3 +238,520 @@ func Test_endTurnAction(t *testing.T) {
 
 	assert.Equal(t, p2, gs.ActivePlayer)
 }
+
+func Test_playOutOfTurn(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+	p2general := p2.GetGeneral()
+
+	spell := NewGenericSpell("Spark", 1, func(owner *Player, gs *gamestate.Gamestate, _ []Unit, _ []Position) {
+		gs.QueueAction(&DamageAction{Unit: p2general, Damage: 1})
+	})
+	artifact := NewArtifact("Sunstone", 1)
+	gremlin := NewUnitFactory().SetName("gremlin").SetUnitType("minion").SetHealth(1).SetAttack(1).SetCost(1)
+
+	// Cards can only be played on their owner's turn
+	assert.ErrorIs(t, spell.Cast(p2, gs, nil, nil), ErrNotYourTurn)
+	assert.ErrorIs(t, artifact.Play(p2, gs), ErrNotYourTurn)
+	_, err := gremlin.Play(p2, gs, NewPosition(7, 2))
+	assert.ErrorIs(t, err, ErrNotYourTurn)
+	assert.Equal(t, 2, p2.Mana)
+	assert.Empty(t, p2.Artifacts())
+	assert.Nil(t, p1.Board.Positions[NewPosition(7, 2)])
+
+	gs.MakeMove(&EndTurnAction{Owner: p1})
+	assert.NoError(t, spell.Cast(p2, gs, nil, nil))
+	assert.ErrorIs(t, spell.Cast(p1, gs, nil, nil), ErrNotYourTurn)
+	assert.Equal(t, 24, p2general.GetHp())
+}
+
+type resolutionLog struct {
+	entries  []string
+	dyingSrc Unit
+	target   Unit
+}
+
+func (rl *resolutionLog) Subscribe(gs *gamestate.Gamestate) {
+	gs.Subscribe(rl)
+}
+
+func (rl *resolutionLog) Unsubscribe(gs *gamestate.Gamestate) {
+	gs.Unsubscribe(rl)
+}
+
+func (rl *resolutionLog) Notify(action gamestate.Action, gs *gamestate.Gamestate) {
+	switch a := action.(type) {
+	case *DamageAction:
+		rl.entries = append(rl.entries, "damage "+a.Unit.GetName())
+	case *RemoveUnitAction:
+		rl.entries = append(rl.entries, "remove "+a.Unit.GetName())
+		// Dying Wish: deal 1 damage to the enemy general
+		if a.Unit == rl.dyingSrc {
+			gs.QueueAction(&DamageAction{Unit: rl.target, Damage: 1})
+		}
+	}
+}
+
+// resolutionBoard sets up three gremlins for player two, the first of which
+// has a Dying Wish that deals 1 damage to its own general.
+func resolutionBoard(resolver gamestate.Resolver) (*Player, *Player, *gamestate.Gamestate, []Unit, *resolutionLog) {
+	p1, p2, gs := setupGamestate()
+	gs.SetResolver(resolver)
+
+	gremlin1 := NewMinion("gremlin1", 1, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: gremlin1, Position: NewPosition(0, 1)})
+	gremlin2 := NewMinion("gremlin2", 1, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: gremlin2, Position: NewPosition(1, 2)})
+	gremlin3 := NewMinion("gremlin3", 1, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: gremlin3, Position: NewPosition(0, 3)})
+
+	log := &resolutionLog{entries: []string{}, dyingSrc: gremlin1, target: p2.GetGeneral()}
+	log.Subscribe(gs)
+
+	return p1, p2, gs, []Unit{gremlin1, gremlin2, gremlin3}, log
+}
+
+func frenzyResolution(resolver gamestate.Resolver) ([]string, *Player, *Player) {
+	p1, p2, gs, gremlins, log := resolutionBoard(resolver)
+	p1general := p1.GetGeneral()
+	p1general.AddAttribute("frenzy", 0)
+
+	gs.MakeMove(&AttackAction{
+		Attacker: p1general,
+		Defender: gremlins[0],
+	})
+
+	return log.entries, p1, p2
+}
+
+// sweepResolution hits each gremlin with its own DamageAction from a spell.
+func sweepResolution(resolver gamestate.Resolver) ([]string, *Player, *Player) {
+	p1, p2, gs, gremlins, log := resolutionBoard(resolver)
+
+	NewGenericSpell("Sweep", 0, func(_ *Player, gs *gamestate.Gamestate, _ []Unit, _ []Position) {
+		for _, gremlin := range gremlins {
+			gs.QueueAction(&DamageAction{Unit: gremlin, Damage: 1})
+		}
+	}).Cast(p1, gs, nil, nil)
+
+	return log.entries, p1, p2
+}
+
+func Test_resolutionStrategies(t *testing.T) {
+	// Sibling damage from a spell resolves in the resolver's order
+	fifo, p1, p2 := sweepResolution(gamestate.NewFIFOResolver())
+	assert.Equal(t, []string{
+		"damage gremlin1", "damage gremlin2", "damage gremlin3",
+		"remove gremlin1", "remove gremlin2", "remove gremlin3",
+		"damage Songhai",
+	}, fifo)
+	assert.Equal(t, 1, len(p2.GetUnits()))
+	assert.Equal(t, 25, p1.GetGeneral().GetHp())
+	assert.Equal(t, 24, p2.GetGeneral().GetHp())
+
+	lifo, p1, p2 := sweepResolution(gamestate.NewLIFOResolver())
+	assert.Equal(t, []string{
+		"damage gremlin3", "remove gremlin3",
+		"damage gremlin2", "remove gremlin2",
+		"damage gremlin1", "remove gremlin1", "damage Songhai",
+	}, lifo)
+	assert.Equal(t, 1, len(p2.GetUnits()))
+	assert.Equal(t, 25, p1.GetGeneral().GetHp())
+	assert.Equal(t, 24, p2.GetGeneral().GetHp())
+
+	// The Dying Wish resolves before the next sibling damage
+	depthFirst, p1, p2 := sweepResolution(gamestate.NewDepthFirstResolver())
+	assert.Equal(t, []string{
+		"damage gremlin1", "remove gremlin1", "damage Songhai",
+		"damage gremlin2", "remove gremlin2",
+		"damage gremlin3", "remove gremlin3",
+	}, depthFirst)
+	assert.Equal(t, 1, len(p2.GetUnits()))
+	assert.Equal(t, 25, p1.GetGeneral().GetHp())
+	assert.Equal(t, 24, p2.GetGeneral().GetHp())
+
+	// Combat damage always resolves as one step, so for an attack the
+	// strategies only differ in how the deaths and their triggers are ordered
+	// afterwards.
+	fifo, p1, p2 = frenzyResolution(gamestate.NewFIFOResolver())
+	assert.Equal(t, []string{
+		"damage gremlin1", "damage gremlin2", "damage gremlin3", "damage Lyonar",
+		"remove gremlin1", "remove gremlin2", "remove gremlin3",
+		"damage Songhai",
+	}, fifo)
+	assert.Equal(t, 1, len(p2.GetUnits()))
+	assert.Equal(t, 24, p1.GetGeneral().GetHp())
+	assert.Equal(t, 24, p2.GetGeneral().GetHp())
+
+	lifo, p1, p2 = frenzyResolution(gamestate.NewLIFOResolver())
+	assert.Equal(t, []string{
+		"damage gremlin1", "damage gremlin2", "damage gremlin3", "damage Lyonar",
+		"remove gremlin3", "remove gremlin2", "remove gremlin1",
+		"damage Songhai",
+	}, lifo)
+	assert.Equal(t, 1, len(p2.GetUnits()))
+	assert.Equal(t, 24, p1.GetGeneral().GetHp())
+	assert.Equal(t, 24, p2.GetGeneral().GetHp())
+
+	// The Dying Wish resolves before the next sibling death
+	depthFirst, p1, p2 = frenzyResolution(gamestate.NewDepthFirstResolver())
+	assert.Equal(t, []string{
+		"damage gremlin1", "damage gremlin2", "damage gremlin3", "damage Lyonar",
+		"remove gremlin1", "damage Songhai",
+		"remove gremlin2", "remove gremlin3",
+	}, depthFirst)
+	assert.Equal(t, 1, len(p2.GetUnits()))
+	assert.Equal(t, 24, p1.GetGeneral().GetHp())
+	assert.Equal(t, 24, p2.GetGeneral().GetHp())
+}
+
+func Test_phaseListener(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+
+	gremlin := NewMinion("gremlin", 2, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: gremlin, Position: NewPosition(1, 2)})
+
+	wouldDie := []Unit{}
+	died := []Unit{}
+	NewPhaseListener(
+		func(action gamestate.Action, before []UnitView, _ *gamestate.Gamestate) {
+			// When this would die: the damage has not been dealt yet
+			if da, ok := action.(*DamageAction); ok && da.Damage >= before[0].Hp {
+				wouldDie = append(wouldDie, before[0].Unit)
+			}
+		},
+		func(action gamestate.Action, before []UnitView, after []UnitView, _ *gamestate.Gamestate) {
+			if _, ok := action.(*RemoveUnitAction); ok && before[0].Alive && !after[0].Alive {
+				died = append(died, after[0].Unit)
+			}
+		},
+	).Subscribe(gs)
+
+	// Listeners without the before hook are still notified afterwards
+	attacks := 0
+	NewUntilEndOfTurnListener(func(action gamestate.Action, _ *gamestate.Gamestate) bool {
+		_, ok := action.(*AttackAction)
+		return ok
+	}, func(_ gamestate.Listener, _ gamestate.Action, _ *gamestate.Gamestate) {
+		attacks++
+	}).Subscribe(gs)
+
+	gs.MakeMove(&AttackAction{Attacker: p1.GetGeneral(), Defender: gremlin})
+
+	assert.Equal(t, 1, attacks)
+	assert.Equal(t, []Unit{gremlin}, wouldDie)
+	assert.Equal(t, []Unit{gremlin}, died)
+	assert.Equal(t, 24, p1.GetGeneral().GetHp())
+}
+
+func Test_causalChain(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+	p1general := p1.GetGeneral()
+
+	gremlin := NewMinion("gremlin", 1, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: gremlin, Position: NewPosition(1, 2)})
+	goblin := NewMinion("goblin", 1, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: goblin, Position: NewPosition(5, 2)})
+
+	// Was this unit killed by an attack from my general?
+	killedByGeneral := map[Unit]bool{}
+	NewUntilEndOfTurnListener(func(action gamestate.Action, _ *gamestate.Gamestate) bool {
+		_, ok := action.(*RemoveUnitAction)
+		return ok
+	}, func(_ gamestate.Listener, action gamestate.Action, game *gamestate.Gamestate) {
+		_, ok := game.Current().Ancestor(func(a gamestate.Action) bool {
+			aa, ok := a.(*AttackAction)
+			return ok && aa.Attacker == p1general
+		})
+		killedByGeneral[action.(*RemoveUnitAction).Unit] = ok
+	}).Subscribe(gs)
+
+	gs.MakeMove(&AttackAction{Attacker: p1general, Defender: gremlin})
+	attackLog := gs.Log()
+	NewGenericSpell("Bolt", 1, func(owner *Player, game *gamestate.Gamestate, units []Unit, _ []Position) {
+		game.QueueAction(&DamageAction{Unit: units[0], Damage: 1})
+	}).Cast(p1, gs, []Unit{goblin}, nil)
+
+	assert.Equal(t, map[Unit]bool{gremlin: true, goblin: false}, killedByGeneral)
+
+	// Walk the attack chain from the log
+	var attack *gamestate.QueuedAction
+	for _, entry := range attackLog {
+		if _, ok := entry.Action.(*AttackAction); ok {
+			attack = entry
+		}
+	}
+
+	assert.True(t, attack.IsRoot())
+	var combat *gamestate.QueuedAction
+	children := []gamestate.Action{}
+	var removal *gamestate.QueuedAction
+	for _, entry := range attackLog {
+		if entry.Parent == attack {
+			combat = entry
+		}
+		if combat != nil && entry.Parent == combat {
+			children = append(children, entry.Action)
+		}
+		if ra, ok := entry.Action.(*RemoveUnitAction); ok && ra.Unit == gremlin {
+			removal = entry
+		}
+	}
+
+	counter := combat.Action.(*CombatDamageAction).Counterattack
+	assert.Equal(t, []gamestate.Action{
+		counter,
+		&DamageAction{Unit: gremlin, Damage: 2},
+		&DamageAction{Unit: p1general, Damage: 1},
+	}, children)
+	assert.Equal(t, attack, removal.Root)
+	assert.Equal(t, 3, removal.Depth)
+}
+
+func Test_changeOwner(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+
+	hits := 0
+	gremlin := NewMinion("gremlin", 3, 1)
+	gremlin.AddActionTrigger(ActionTrigger{
+		Trigger: func(self Unit, action gamestate.Action, _ *gamestate.Gamestate) {
+			if da, ok := action.(*DamageAction); ok && da.Unit == self {
+				hits++
+			}
+		},
+		CanDispel: true,
+	})
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: gremlin, Position: NewPosition(5, 2)})
+	gs.MakeMove(&EndTurnAction{Owner: p1})
+	gs.MakeMove(&EndTurnAction{Owner: p2})
+	gremlin.BuffAttack(2)
+
+	assert.False(t, gremlin.IsExhausted())
+	assert.False(t, gremlin.FacesRight())
+
+	gs.MakeMove(&ChangeOwnerAction{Unit: gremlin, Owner: p1})
+
+	assert.Equal(t, p1, gremlin.GetOwner())
+	assert.Equal(t, 2, len(p1.GetUnits()))
+	assert.Equal(t, 1, len(p2.GetUnits()))
+	assert.Equal(t, NewPosition(5, 2), gremlin.GetPosition())
+	assert.Equal(t, 3, gremlin.GetAttack())
+	assert.True(t, gremlin.FacesRight())
+	assert.True(t, gremlin.IsExhausted())
+
+	gs.MakeMove(&DamageAction{Unit: gremlin, Damage: 1})
+	assert.Equal(t, 1, hits)
+
+	// Generals cannot be stolen
+	gs.MakeMove(&ChangeOwnerAction{Unit: p2.GetGeneral(), Owner: p1})
+	assert.True(t, p2.IsAlive())
+}
+
+func Test_transform(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+
+	hits := 0
+	gremlin := NewMinion("gremlin", 3, 4)
+	gremlin.AddActionTrigger(ActionTrigger{
+		Trigger: func(_ Unit, action gamestate.Action, _ *gamestate.Gamestate) {
+			if _, ok := action.(*DamageAction); ok {
+				hits++
+			}
+		},
+		CanDispel: true,
+	})
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: gremlin, Position: NewPosition(5, 2)})
+	gremlin.BuffHealth(2)
+
+	sheep := NewMinion("sheep", 1, 0)
+	gs.MakeMove(&TransformAction{Unit: gremlin, Into: sheep})
+
+	assert.False(t, gremlin.IsAlive())
+	assert.Equal(t, NewPosition(5, 2), sheep.GetPosition())
+	assert.Equal(t, p2, sheep.GetOwner())
+	assert.Equal(t, 1, sheep.GetHp())
+	assert.True(t, sheep.IsExhausted())
+	assert.Equal(t, 2, len(p2.GetUnits()))
+	assert.Equal(t, sheep, p1.Board.Positions[NewPosition(5, 2)])
+
+	gs.MakeMove(&DamageAction{Unit: p1.GetGeneral(), Damage: 1})
+	assert.Equal(t, 0, hits)
+
+	// Generals cannot be transformed
+	p2general := p2.GetGeneral()
+	gs.MakeMove(&TransformAction{Unit: p2general, Into: NewMinion("sheep", 1, 0)})
+	assert.Equal(t, p2general, p1.Board.Positions[NewPosition(8, 2)])
+	assert.Equal(t, p2general, p2.GetGeneral())
+	assert.Equal(t, 2, len(p2.GetUnits()))
+}
+
+func Test_facing(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+
+	assert.True(t, p1.GetGeneral().FacesRight())
+	assert.False(t, p2.GetGeneral().FacesRight())
+
+	gremlin := NewMinion("gremlin", 2, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p1, Unit: gremlin, Position: NewPosition(4, 2)})
+	goblin := NewMinion("goblin", 2, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: goblin, Position: NewPosition(6, 0)})
+
+	assert.True(t, gremlin.FacesRight())
+	assert.False(t, goblin.FacesRight())
+
+	turns := []bool{}
+	NewPhaseListener(nil, func(action gamestate.Action, _ []UnitView, _ []UnitView, _ *gamestate.Gamestate) {
+		if fa, ok := action.(*FaceAction); ok {
+			turns = append(turns, fa.Right)
+		}
+	}).Subscribe(gs)
+
+	// Both minions were summoned exhausted
+	endTurns(gs, p1, p2)
+
+	gs.MakeMove(&MoveAction{Unit: gremlin, Position: NewPosition(3, 2)})
+	assert.False(t, gremlin.FacesRight())
+
+	// Vertical moves keep facing, as does walking forwards
+	endTurns(gs, p1, p2)
+	gs.MakeMove(&MoveAction{Unit: gremlin, Position: NewPosition(3, 0)})
+	assert.False(t, gremlin.FacesRight())
+	endTurns(gs, p1, p2)
+	gs.MakeMove(&MoveAction{Unit: gremlin, Position: NewPosition(2, 0)})
+
+	// Attacking turns towards the target
+	endTurns(gs, p1)
+	gs.MakeMove(&MoveAction{Unit: goblin, Position: NewPosition(3, 0)})
+	endTurns(gs, p2)
+	gs.MakeMove(&AttackAction{Attacker: gremlin, Defender: goblin})
+	assert.True(t, gremlin.FacesRight())
+
+	assert.Equal(t, []bool{false, true}, turns)
+}
+
+func Test_backstabBothSides(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+	p1general := p1.GetGeneral()
+	p2general := p2.GetGeneral()
+
+	p2general.AddAttribute("backstab", 2)
+	p1general.AddAttribute("backstab", 2)
+
+	// Player two walks around behind player one
+	gs.MakeMove(&MoveAction{Unit: p1general, Position: NewPosition(4, 2)})
+	endTurns(gs, p1)
+	gs.MakeMove(&MoveAction{Unit: p2general, Position: NewPosition(3, 2)})
+
+	assert.True(t, p1general.FacesRight())
+	assert.False(t, p2general.FacesRight())
+
+	gs.MakeMove(&AttackAction{Attacker: p2general, Defender: p1general})
+
+	// Backstabbed units do not counterattack
+	assert.Equal(t, 21, p1general.GetHp())
+	assert.Equal(t, 25, p2general.GetHp())
+	assert.True(t, p2general.FacesRight())
+
+	// Now face to face, player one strikes back from the front
+	endTurns(gs, p2)
+	gs.MakeMove(&AttackAction{Attacker: p1general, Defender: p2general})
+
+	assert.False(t, p1general.FacesRight())
+	assert.Equal(t, 23, p2general.GetHp())
+	assert.Equal(t, 19, p1general.GetHp())
+}
+
+func Test_counterattackRules(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+	p1general := p1.GetGeneral()
+	p2general := p2.GetGeneral()
+	p1general.AddAttribute("ranged", 0)
+
+	// Melee units cannot strike back at range
+	gs.MakeMove(&AttackAction{Attacker: p1general, Defender: p2general})
+	assert.Equal(t, 25, p1general.GetHp())
+
+	// Ranged units can
+	p2general.AddAttribute("ranged", 0)
+	endTurns(gs, p1, p2)
+	gs.MakeMove(&AttackAction{Attacker: p1general, Defender: p2general})
+	assert.Equal(t, 23, p1general.GetHp())
+
+	// Stunned units cannot
+	p2general.AddAttribute("stunned", 0)
+	endTurns(gs, p1, p2)
+	gs.MakeMove(&AttackAction{Attacker: p1general, Defender: p2general})
+	assert.Equal(t, 23, p1general.GetHp())
+	assert.Equal(t, 19, p2general.GetHp())
+}
+
+func Test_counterattackInterceptors(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+	p1general := p1.GetGeneral()
+	p2general := p2.GetGeneral()
+	gs.MakeMove(&MoveAction{Unit: p1general, Position: NewPosition(7, 2)})
+
+	// This turn, enemy counterattacks deal double
+	NewUntilEndOfTurnInterceptor(func(action gamestate.Action, _ *gamestate.Gamestate) bool {
+		ca, ok := action.(*CounterattackAction)
+		return ok && ca.Unit.GetOwner() == p2
+	}, func(_ gamestate.Interceptor, action gamestate.Action, _ *gamestate.Gamestate) gamestate.Action {
+		action.(*CounterattackAction).Damage *= 2
+		return action
+	}).Subscribe(gs)
+
+	gs.MakeMove(&AttackAction{Attacker: p1general, Defender: p2general})
+	assert.Equal(t, 21, p1general.GetHp())
+	assert.Equal(t, 23, p2general.GetHp())
+
+	gs.MakeMove(&EndTurnAction{Owner: p1})
+
+	// This unit can't counterattack
+	NewUntilEndOfTurnInterceptor(func(action gamestate.Action, _ *gamestate.Gamestate) bool {
+		ca, ok := action.(*CounterattackAction)
+		return ok && ca.Unit == p1general
+	}, func(self gamestate.Interceptor, action gamestate.Action, _ *gamestate.Gamestate) gamestate.Action {
+		return gamestate.Cancel(action, self)
+	}).Subscribe(gs)
+
+	gs.MakeMove(&AttackAction{Attacker: p2general, Defender: p1general})
+	assert.Equal(t, 19, p1general.GetHp())
+	assert.Equal(t, 23, p2general.GetHp())
+}
+
+func Test_simultaneousCombatDamage(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+	gs.SetResolver(gamestate.NewDepthFirstResolver())
+
+	gremlin := NewMinion("gremlin", 4, 2)
+	gs.MakeMove(&PlaceUnitAction{Owner: p1, Unit: gremlin, Position: NewPosition(4, 2)})
+	goblin := NewMinion("goblin", 2, 2)
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: goblin, Position: NewPosition(5, 2)})
+	endTurns(gs, p1, p2)
+
+	// Enrage: when the goblin is damaged it gains +3 attack. This must not
+	// change the counterattack that was fixed when the attack was made.
+	NewUntilEndOfTurnListener(func(action gamestate.Action, _ *gamestate.Gamestate) bool {
+		da, ok := action.(*DamageAction)
+		return ok && da.Unit == goblin
+	}, func(_ gamestate.Listener, _ gamestate.Action, _ *gamestate.Gamestate) {
+		goblin.BuffAttack(3)
+	}).Subscribe(gs)
+
+	hits := []string{}
+	removedWhileHit := false
+	NewUntilEndOfTurnListener(func(action gamestate.Action, _ *gamestate.Gamestate) bool {
+		_, ok := action.(*DamageAction)
+		return ok
+	}, func(_ gamestate.Listener, action gamestate.Action, _ *gamestate.Gamestate) {
+		hits = append(hits, action.(*DamageAction).Unit.GetName())
+		removedWhileHit = removedWhileHit || !goblin.IsAlive()
+	}).Subscribe(gs)
+
+	gs.MakeMove(&AttackAction{Attacker: gremlin, Defender: goblin})
+
+	// The goblin still struck back for 2 and was only removed afterwards
+	assert.Equal(t, []string{"goblin", "gremlin"}, hits)
+	assert.False(t, removedWhileHit)
+	assert.Equal(t, 2, gremlin.GetHp())
+	assert.False(t, goblin.IsAlive())
+}
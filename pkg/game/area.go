@@ -0,0 +1,148 @@
+package game
+
+import "sort"
+
+// TileShape picks the tiles of an area around a position.
+type TileShape func(ub *UnitBoard, center Position) []Position
+
+// tilesWhere lists the tiles on the board that match, row by row, so that
+// effects built on a shape resolve in a fixed order.
+func (ub *UnitBoard) tilesWhere(match func(Position) bool) []Position {
+	tiles := []Position{}
+	for y := 0; y < ub.BoardY; y++ {
+		for x := 0; x < ub.BoardX; x++ {
+			if pos := NewPosition(x, y); match(pos) {
+				tiles = append(tiles, pos)
+			}
+		}
+	}
+
+	return tiles
+}
+
+func (ub *UnitBoard) AllTiles() []Position {
+	return ub.tilesWhere(func(Position) bool {
+		return true
+	})
+}
+
+func (ub *UnitBoard) RowTiles(y int) []Position {
+	return ub.tilesWhere(func(pos Position) bool {
+		return pos.Y == y
+	})
+}
+
+func (ub *UnitBoard) ColumnTiles(x int) []Position {
+	return ub.tilesWhere(func(pos Position) bool {
+		return pos.X == x
+	})
+}
+
+// SquareTiles is the 3x3 area centered on a tile, the tile included.
+func (ub *UnitBoard) SquareTiles(center Position) []Position {
+	return ub.tilesWhere(func(pos Position) bool {
+		absDiff := pos.Diff(center).Abs()
+		return max(absDiff.X, absDiff.Y) <= 1
+	})
+}
+
+// Square2x2Tiles is the 2x2 area with the given top left corner. It is short
+// when the corner is on the last row or column.
+func (ub *UnitBoard) Square2x2Tiles(corner Position) []Position {
+	return ub.tilesWhere(func(pos Position) bool {
+		diff := pos.Diff(corner)
+		return diff.X >= 0 && diff.X <= 1 && diff.Y >= 0 && diff.Y <= 1
+	})
+}
+
+// CrossTiles is a tile and the four tiles beside, above and below it.
+func (ub *UnitBoard) CrossTiles(center Position) []Position {
+	return ub.tilesWhere(func(pos Position) bool {
+		absDiff := pos.Diff(center).Abs()
+		return absDiff.X+absDiff.Y <= 1
+	})
+}
+
+// DiagonalTiles is every tile on either diagonal through a tile, the tile
+// itself excluded.
+func (ub *UnitBoard) DiagonalTiles(center Position) []Position {
+	return ub.tilesWhere(func(pos Position) bool {
+		absDiff := pos.Diff(center).Abs()
+		return absDiff.X == absDiff.Y && absDiff.X > 0
+	})
+}
+
+// NearbyTiles is the eight tiles around a tile.
+func (ub *UnitBoard) NearbyTiles(center Position) []Position {
+	return ub.tilesWhere(func(pos Position) bool {
+		absDiff := pos.Diff(center).Abs()
+		return max(absDiff.X, absDiff.Y) == 1
+	})
+}
+
+// RayTiles walks from a tile, excluded, in steps of direction until the edge of
+// the board. Unlike the other shapes its tiles are ordered by distance.
+func (ub *UnitBoard) RayTiles(from Position, direction Position) []Position {
+	tiles := []Position{}
+	if direction == NewPosition(0, 0) {
+		return tiles
+	}
+
+	for pos := from.Add(direction); pos.IsOnBoard(ub); pos = pos.Add(direction) {
+		tiles = append(tiles, pos)
+	}
+
+	return tiles
+}
+
+// SideTiles is the player's half of the board. The middle column of an odd
+// width board belongs to neither side.
+func (ub *UnitBoard) SideTiles(player *Player) []Position {
+	return ub.tilesWhere(func(pos Position) bool {
+		return ub.isOnSide(pos, player.FacesRight)
+	})
+}
+
+// EnemySideTiles is the half of the board facing the player.
+func (ub *UnitBoard) EnemySideTiles(player *Player) []Position {
+	return ub.tilesWhere(func(pos Position) bool {
+		return ub.isOnSide(pos, !player.FacesRight)
+	})
+}
+
+// isOnSide reports whether a tile is on the left half of the board, or the
+// right half when left is false.
+func (ub *UnitBoard) isOnSide(pos Position, left bool) bool {
+	half := ub.BoardX / 2
+	if left {
+		return pos.X < half
+	}
+
+	return pos.X >= ub.BoardX-half
+}
+
+// UnitsIn lists the units on the tiles, ordered by row then column.
+func (ub *UnitBoard) UnitsIn(tiles []Position) []Unit {
+	units := []Unit{}
+	for _, tile := range tiles {
+		if unit, ok := ub.Positions[tile]; ok {
+			units = append(units, unit)
+		}
+	}
+
+	sort.Slice(units, func(i, j int) bool {
+		return positionLess(ub.Units[units[i]], ub.Units[units[j]])
+	})
+
+	return units
+}
+
+func containsTile(tiles []Position, tile Position) bool {
+	for _, pos := range tiles {
+		if pos == tile {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,73 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/RGood/game_engine/pkg/gamestate"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_tileShapes(t *testing.T) {
+	p1, p2, _ := setupGamestate()
+	board := p1.Board
+
+	assert.Len(t, board.AllTiles(), 45)
+	assert.Len(t, board.RowTiles(2), 9)
+	assert.Len(t, board.ColumnTiles(4), 5)
+	assert.Len(t, board.SquareTiles(NewPosition(4, 2)), 9)
+	assert.Len(t, board.SquareTiles(NewPosition(0, 0)), 4)
+	assert.Len(t, board.NearbyTiles(NewPosition(4, 2)), 8)
+	assert.Equal(t, []Position{
+		NewPosition(8, 3),
+		NewPosition(7, 4),
+		NewPosition(8, 4),
+	}, board.Square2x2Tiles(NewPosition(7, 3))[1:])
+	assert.Equal(t, []Position{
+		NewPosition(4, 1),
+		NewPosition(3, 2),
+		NewPosition(4, 2),
+		NewPosition(5, 2),
+		NewPosition(4, 3),
+	}, board.CrossTiles(NewPosition(4, 2)))
+	assert.Equal(t, []Position{
+		NewPosition(0, 0),
+		NewPosition(4, 0),
+		NewPosition(1, 1),
+		NewPosition(3, 1),
+		NewPosition(1, 3),
+		NewPosition(3, 3),
+		NewPosition(0, 4),
+		NewPosition(4, 4),
+	}, board.DiagonalTiles(NewPosition(2, 2)))
+	assert.Equal(t, []Position{
+		NewPosition(3, 2),
+		NewPosition(2, 2),
+		NewPosition(1, 2),
+		NewPosition(0, 2),
+	}, board.RayTiles(NewPosition(4, 2), NewPosition(-1, 0)))
+
+	assert.Len(t, board.SideTiles(p1), 20)
+	assert.Equal(t, board.SideTiles(p2), board.EnemySideTiles(p1))
+	assert.Equal(t, []Unit{p2.GetGeneral()}, board.UnitsIn(board.EnemySideTiles(p1)))
+}
+
+func Test_areaSpell(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+
+	inside := NewMinion("inside", 2, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: inside, Position: NewPosition(5, 1)})
+	outside := NewMinion("outside", 2, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: outside, Position: NewPosition(6, 2)})
+
+	var spell *GenericSpell
+	spell = NewGenericSpell("Tempest", 2, func(owner *Player, gs *gamestate.Gamestate, _ []Unit, tiles []Position) {
+		for _, unit := range owner.Board.UnitsIn(spell.Targeting.AffectedTiles(owner, tiles)) {
+			gs.QueueAction(&DamageAction{Unit: unit, Damage: 2})
+		}
+	})
+	spell.Targeting = TargetSpec{Kind: TargetArea}
+
+	assert.NoError(t, spell.Cast(p1, gs, nil, []Position{NewPosition(4, 1)}))
+	assert.False(t, inside.IsAlive())
+	assert.True(t, outside.IsAlive())
+}
@@ -1,31 +1,67 @@
 package game
 
-import "github.com/RGood/game_engine/pkg/gamestate"
+import (
+	"encoding/json"
+
+	"github.com/RGood/game_engine/pkg/gamestate"
+)
+
+// MaxArtifacts is how many artifacts a player can have equipped. Equipping
+// another destroys the oldest.
+const MaxArtifacts = 3
+
+// DurabilityRule reports how many charges an action costs an equipped
+// artifact. The artifact breaks when it runs out.
+type DurabilityRule func(artifact *Artifact, action gamestate.Action) int
+
+// GeneralDamaged costs a charge each time the owner's general takes damage.
+func GeneralDamaged(artifact *Artifact, action gamestate.Action) int {
+	damageAction, ok := action.(*DamageAction)
+	if ok && damageAction.Damage > 0 && damageAction.Unit.GetOwner() == artifact.Owner && damageAction.Unit.GetType() == "general" {
+		return 1
+	}
+
+	return 0
+}
 
 type Artifact struct {
-	Name      string
-	Cost      int
-	Charges   int
-	Owner     *Player
-	intercept func(*Artifact, gamestate.Action, *gamestate.Gamestate) gamestate.Action
-	notify    func(*Artifact, gamestate.Action, *gamestate.Gamestate)
-	onEquip   func(*Artifact, *gamestate.Gamestate)
-	onUnequip func(*Artifact, *gamestate.Gamestate)
+	id         gamestate.EntityID
+	Name       string
+	Cost       int
+	Charges    int
+	CanDispel  bool
+	Owner      *Player
+	durability DurabilityRule
+	intercept  func(*Artifact, gamestate.Action, *gamestate.Gamestate) gamestate.Action
+	notify     func(*Artifact, gamestate.Action, *gamestate.Gamestate)
+	onEquip    func(*Artifact, *gamestate.Gamestate)
+	onUnequip  func(*Artifact, *gamestate.Gamestate)
 }
 
 func NewArtifact(name string, cost int) *Artifact {
 	return &Artifact{
-		Name:      name,
-		Cost:      cost,
-		Charges:   3,
-		Owner:     nil,
-		intercept: nil,
-		notify:    nil,
-		onEquip:   nil,
-		onUnequip: nil,
+		Name:       name,
+		Cost:       cost,
+		Charges:    3,
+		CanDispel:  true,
+		Owner:      nil,
+		durability: GeneralDamaged,
+		intercept:  nil,
+		notify:     nil,
+		onEquip:    nil,
+		onUnequip:  nil,
 	}
 }
 
+// WithDurability sets the artifact's charges and what uses them up. A nil rule
+// never breaks the artifact.
+func (artifact *Artifact) WithDurability(charges int, rule DurabilityRule) *Artifact {
+	artifact.Charges = charges
+	artifact.durability = rule
+
+	return artifact
+}
+
 func (artifact *Artifact) OnEquip(effect func(*Artifact, *gamestate.Gamestate)) *Artifact {
 	artifact.onEquip = effect
 
@@ -50,8 +86,71 @@ func (artifact *Artifact) OnIntercept(effect func(*Artifact, gamestate.Action, *
 	return artifact
 }
 
+func (artifact *Artifact) GetID() gamestate.EntityID {
+	return artifact.id
+}
+
+func (artifact *Artifact) GetCost() int {
+	return artifact.Cost
+}
+
+// Play pays for the artifact from its owner's mana and equips it.
+func (artifact *Artifact) Play(owner *Player, gs *gamestate.Gamestate) error {
+	if err := owner.canPlay(gs); err != nil {
+		return err
+	}
+
+	if _, ok := owner.payForCard(artifact); !ok {
+		return ErrInsufficientMana
+	}
+	owner.leaveHand(artifact)
+
+	gs.MakeMove(&EquipArtifactAction{
+		Owner:    owner,
+		Artifact: artifact,
+	})
+
+	return nil
+}
+
+func (artifact *Artifact) assignID(ub *UnitBoard) {
+	if artifact.id == 0 {
+		artifact.id = ub.NewID(artifact)
+	}
+}
+
+// artifactJSON is how an artifact is serialized, with its owner as an ID.
+type artifactJSON struct {
+	ID        gamestate.EntityID
+	Name      string
+	Cost      int
+	Charges   int
+	CanDispel bool
+	Owner     gamestate.EntityID
+}
+
+func (artifact *Artifact) MarshalJSON() ([]byte, error) {
+	return json.Marshal(artifactJSON{
+		ID:        artifact.id,
+		Name:      artifact.Name,
+		Cost:      artifact.Cost,
+		Charges:   artifact.Charges,
+		CanDispel: artifact.CanDispel,
+		Owner:     artifact.Owner.GetID(),
+	})
+}
+
 func (artifact *Artifact) Equip(owner *Player, gamestate *gamestate.Gamestate) {
+	owner.enterZone(artifact)
+
+	if len(owner.artifacts) >= MaxArtifacts {
+		gamestate.ResolveNow(&RemoveArtifactAction{
+			Artifact: owner.artifacts[0],
+		})
+	}
+
 	artifact.Owner = owner
+	owner.artifacts = append(owner.artifacts, artifact)
 	artifact.AddIntercept(gamestate)
 	artifact.Subscribe(gamestate)
 
@@ -61,12 +160,18 @@ func (artifact *Artifact) Equip(owner *Player, gamestate *gamestate.Gamestate) {
 }
 
 func (artifact *Artifact) Remove(gamestate *gamestate.Gamestate) {
+	// Several things can break an artifact in the same chain
+	if artifact.Owner == nil {
+		return
+	}
+
 	if artifact.onUnequip != nil {
 		artifact.onUnequip(artifact, gamestate)
 	}
 
 	artifact.RemoveIntercept(gamestate)
 	artifact.Unsubscribe(gamestate)
+	artifact.Owner.removeArtifact(artifact)
 	artifact.Owner = nil
 }
 
@@ -94,21 +199,44 @@ func (artifact *Artifact) Apply(action gamestate.Action, gamestate *gamestate.Ga
 	return action
 }
 
+// Notify runs the artifact's own trigger, then breaks it if it ran out of
+// charges, its owner's general died, or the general was dispelled.
 func (artifact *Artifact) Notify(action gamestate.Action, gamestate *gamestate.Gamestate) {
+	if artifact.Owner == nil {
+		return
+	}
+
 	if artifact.notify != nil {
 		artifact.notify(artifact, action, gamestate)
 	}
 
-	damageAction, ok := action.(*DamageAction)
-
-	if ok && damageAction.Damage > 0 && damageAction.Unit.GetOwner() == artifact.Owner && damageAction.Unit.GetType() == "general" {
-		artifact.Charges--
+	if artifact.durability != nil && artifact.Charges > 0 {
+		artifact.Charges -= artifact.durability(artifact, action)
+		if artifact.Charges <= 0 {
+			artifact.Charges = 0
+			artifact.destroy(gamestate)
+		}
+	}
 
-		if artifact.Charges == 0 {
-			gamestate.MakeMove(&RemoveArtifactAction{
-				Artifact: artifact,
-			})
+	switch action := action.(type) {
+	case *RemoveUnitAction:
+		// The removed unit no longer has an owner, so check the player instead
+		if !artifact.Owner.IsAlive() {
+			artifact.destroy(gamestate)
+		}
+	case *DispelAction:
+		if artifact.CanDispel && artifact.isOwnGeneral(action.Unit) {
+			artifact.destroy(gamestate)
 		}
 	}
+}
+
+func (artifact *Artifact) isOwnGeneral(unit Unit) bool {
+	return unit.GetOwner() == artifact.Owner && unit.GetType() == "general"
+}
 
+func (artifact *Artifact) destroy(gamestate *gamestate.Gamestate) {
+	gamestate.QueueAction(&RemoveArtifactAction{
+		Artifact: artifact,
+	})
 }
@@ -100,6 +100,13 @@ func Test_arclyteRegalia(t *testing.T) {
 		return action
 	})
 
+	// Both of p2's attackers strike twice a turn, so every hit below lands
+	// within one turn
+	p2general.AddAttribute(Celerity, 0)
+	goblin := NewMinion("goblin", 10, 2)
+	goblin.AddAttribute(Celerity, 0)
+	goblin.Place(p2, NewPosition(7, 3))
+
 	arclyteRegalia.Equip(p1, gs)
 
 	assert.Equal(t, 4, p1general.GetAttack())
@@ -118,7 +125,7 @@ func Test_arclyteRegalia(t *testing.T) {
 	assert.Equal(t, 21, p2general.GetHp())
 	assert.Equal(t, 25, p1general.GetHp())
 
-	gs.MakeMove(&EndTurnAction{})
+	gs.MakeMove(&EndTurnAction{Owner: p1})
 
 	gs.MakeMove(&AttackAction{
 		Attacker: p2general,
@@ -138,12 +145,12 @@ func Test_arclyteRegalia(t *testing.T) {
 	assert.Equal(t, 2, arclyteRegalia.Charges)
 
 	gs.MakeMove(&AttackAction{
-		Attacker: p2general,
+		Attacker: goblin,
 		Defender: p1general,
 	})
 
 	gs.MakeMove(&AttackAction{
-		Attacker: p2general,
+		Attacker: goblin,
 		Defender: p1general,
 	})
 
@@ -151,3 +158,58 @@ func Test_arclyteRegalia(t *testing.T) {
 	assert.Equal(t, 2, p1general.GetAttack())
 
 }
+
+func Test_artifactSlots(t *testing.T) {
+	p1, _, gs := setupGamestate()
+
+	first := NewArtifact("First", 0)
+	second := NewArtifact("Second", 0)
+	third := NewArtifact("Third", 0)
+	fourth := NewArtifact("Fourth", 0)
+	for _, artifact := range []*Artifact{first, second, third} {
+		gs.MakeMove(&EquipArtifactAction{Owner: p1, Artifact: artifact})
+	}
+	assert.Equal(t, []*Artifact{first, second, third}, p1.Artifacts())
+
+	// A fourth artifact replaces the oldest
+	gs.MakeMove(&EquipArtifactAction{Owner: p1, Artifact: fourth})
+	assert.Equal(t, []*Artifact{second, third, fourth}, p1.Artifacts())
+	assert.Nil(t, first.Owner)
+}
+
+func Test_artifactDurabilityRules(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+	p1general := p1.GetGeneral()
+
+	// Loses a charge whenever its owner casts a spell
+	focus := NewArtifact("Focus", 0).WithDurability(2, func(artifact *Artifact, action gamestate.Action) int {
+		if spellAction, ok := action.(*SpellAction); ok && spellAction.Owner == artifact.Owner {
+			return 1
+		}
+		return 0
+	})
+	sturdy := NewArtifact("Sturdy", 0).WithDurability(1, nil)
+	focus.Equip(p1, gs)
+	sturdy.Equip(p1, gs)
+
+	nullSpell := NewGenericSpell("Test", 0, func(*Player, *gamestate.Gamestate, []Unit, []Position) {})
+	gs.MakeMove(&DamageAction{Unit: p1general, Damage: 3})
+	nullSpell.Cast(p1, gs, nil, nil)
+	assert.Equal(t, 1, focus.Charges)
+	nullSpell.Cast(p1, gs, nil, nil)
+	assert.Equal(t, []*Artifact{sturdy}, p1.Artifacts())
+
+	// Dispelling the general strips dispellable artifacts
+	ward := NewArtifact("Ward", 0)
+	ward.CanDispel = false
+	ward.Equip(p1, gs)
+	gs.MakeMove(&DispelAction{Unit: p1general})
+	assert.Equal(t, []*Artifact{ward}, p1.Artifacts())
+
+	// Everything goes when the general dies
+	armor := NewArtifact("Armor", 0)
+	armor.Equip(p2, gs)
+	gs.MakeMove(&DamageAction{Unit: p1general, Damage: 30})
+	assert.Empty(t, p1.Artifacts())
+	assert.Equal(t, []*Artifact{armor}, p2.Artifacts())
+}
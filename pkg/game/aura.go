@@ -0,0 +1,202 @@
+package game
+
+import (
+	"sort"
+
+	"github.com/RGood/game_engine/pkg/gamestate"
+)
+
+// Modifier is a set of stat and keyword changes an aura grants.
+type Modifier struct {
+	Attack     int
+	Health     int
+	Attributes map[Keyword]int
+}
+
+// Aura is a continuous effect from a source unit. After every action chain
+// the units matching the filter are recomputed: new ones gain the modifier and
+// ones that left the area lose it. The aura ends when its source leaves the
+// board, or is dispelled if CanDispel is set.
+type Aura struct {
+	Source    Unit
+	Filter    func(source Unit, target Unit) bool
+	Modifier  Modifier
+	CanDispel bool
+	affected  map[Unit]*auraGrant
+}
+
+// auraGrant is what an aura gave one unit. Dispelling the unit strips the
+// stats and dispellable keywords but not the rest, so the grant keeps the
+// keywords that survived and is marked for the next settle to apply again.
+type auraGrant struct {
+	keywords  map[Keyword]struct{}
+	dispelled bool
+}
+
+func NewAura(source Unit, filter func(Unit, Unit) bool, modifier Modifier, canDispel bool) *Aura {
+	if modifier.Attributes == nil {
+		modifier.Attributes = map[Keyword]int{}
+	}
+
+	return &Aura{
+		Source:    source,
+		Filter:    filter,
+		Modifier:  modifier,
+		CanDispel: canDispel,
+		affected:  map[Unit]*auraGrant{},
+	}
+}
+
+// FriendlyMinionsIn matches the source's other friendly minions within a shape
+// around it.
+func FriendlyMinionsIn(shape TileShape) func(Unit, Unit) bool {
+	return func(source Unit, target Unit) bool {
+		if target == source || source.IsEnemy(target) || target.GetType() == "general" {
+			return false
+		}
+
+		return containsTile(shape(source.GetBoard(), source.GetPosition()), target.GetPosition())
+	}
+}
+
+func NearbyFriendlyMinions(source Unit, target Unit) bool {
+	return FriendlyMinionsIn((*UnitBoard).NearbyTiles)(source, target)
+}
+
+func FriendlyGeneral(source Unit, target Unit) bool {
+	return !source.IsEnemy(target) && target.GetType() == "general"
+}
+
+func (aura *Aura) Subscribe(game *gamestate.Gamestate) {
+	game.Subscribe(aura)
+}
+
+func (aura *Aura) Unsubscribe(game *gamestate.Gamestate) {
+	game.Unsubscribe(aura)
+}
+
+// Affects reports whether the unit currently has this aura's modifier.
+func (aura *Aura) Affects(unit Unit) bool {
+	grant, ok := aura.affected[unit]
+	return ok && !grant.dispelled
+}
+
+func (aura *Aura) Notify(action gamestate.Action, game *gamestate.Gamestate) {
+	dispelAction, ok := action.(*DispelAction)
+	if !ok {
+		return
+	}
+
+	if dispelAction.Unit == aura.Source && aura.CanDispel {
+		aura.end(game)
+		return
+	}
+
+	// Dispel already stripped our stats and dispellable keywords from the
+	// unit; the next settle will grant them again.
+	if grant, ok := aura.affected[dispelAction.Unit]; ok {
+		for attr, _ := range grant.keywords {
+			if !dispelAction.Unit.HasAttribute(attr) {
+				delete(grant.keywords, attr)
+			}
+		}
+		grant.dispelled = true
+	}
+}
+
+func (aura *Aura) Settle(game *gamestate.Gamestate) {
+	if !aura.Source.IsAlive() {
+		aura.end(game)
+		return
+	}
+
+	for _, unit := range aura.affectedUnits() {
+		if !unit.IsAlive() || !aura.Filter(aura.Source, unit) {
+			aura.unapply(unit, game)
+		}
+	}
+
+	for _, unit := range aura.Source.GetBoard().SortedUnits() {
+		if !aura.Affects(unit) && aura.Filter(aura.Source, unit) {
+			aura.apply(unit)
+		}
+	}
+}
+
+func (aura *Aura) end(game *gamestate.Gamestate) {
+	for _, unit := range aura.affectedUnits() {
+		aura.unapply(unit, game)
+	}
+
+	aura.Unsubscribe(game)
+}
+
+// affectedUnits lists the units under the aura by ID, so that removals queue
+// in the same order on every replay.
+func (aura *Aura) affectedUnits() []Unit {
+	units := []Unit{}
+	for unit, _ := range aura.affected {
+		units = append(units, unit)
+	}
+
+	sort.Slice(units, func(i, j int) bool {
+		return units[i].GetID() < units[j].GetID()
+	})
+
+	return units
+}
+
+func (aura *Aura) apply(unit Unit) {
+	grant, ok := aura.affected[unit]
+	if !ok {
+		grant = &auraGrant{keywords: map[Keyword]struct{}{}}
+		aura.affected[unit] = grant
+	}
+
+	for _, attr := range sortedKeywords(aura.Modifier.Attributes) {
+		if _, kept := grant.keywords[attr]; kept {
+			continue
+		}
+
+		// Never take away a keyword the unit had on its own
+		if !unit.HasAttribute(attr) && unit.AddAttribute(attr, aura.Modifier.Attributes[attr]) == nil {
+			grant.keywords[attr] = struct{}{}
+		}
+	}
+
+	unit.BuffAttack(aura.Modifier.Attack)
+	unit.BuffHealth(aura.Modifier.Health)
+	grant.dispelled = false
+}
+
+func (aura *Aura) unapply(unit Unit, game *gamestate.Gamestate) {
+	grant := aura.affected[unit]
+	for attr, _ := range grant.keywords {
+		unit.RemoveAttribute(attr)
+	}
+
+	if !grant.dispelled {
+		unit.BuffAttack(-aura.Modifier.Attack)
+		unit.BuffHealth(-aura.Modifier.Health)
+	}
+	delete(aura.affected, unit)
+
+	if unit.IsAlive() && unit.GetHp() <= 0 {
+		game.QueueAction(&RemoveUnitAction{
+			Unit: unit,
+		})
+	}
+}
+
+func sortedKeywords(attributes map[Keyword]int) []Keyword {
+	keywords := []Keyword{}
+	for attr, _ := range attributes {
+		keywords = append(keywords, attr)
+	}
+
+	sort.Slice(keywords, func(i, j int) bool {
+		return keywords[i] < keywords[j]
+	})
+
+	return keywords
+}
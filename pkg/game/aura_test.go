@@ -0,0 +1,125 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/RGood/game_engine/pkg/gamestate"
+	"github.com/stretchr/testify/assert"
+)
+
+// newAuraMinion creates a minion that starts its aura once it is summoned
+func newAuraMinion(name string, hp int, attack int, filter func(Unit, Unit) bool, modifier Modifier) Unit {
+	return NewUnitFactory().SetName(name).SetUnitType("minion").SetHealth(hp).SetAttack(attack).AddTrigger(ActionTrigger{
+		Trigger: func(self Unit, action gamestate.Action, gs *gamestate.Gamestate) {
+			if pa, ok := action.(*PlaceUnitAction); ok && pa.Unit == self {
+				NewAura(self, filter, modifier, true).Subscribe(gs)
+			}
+		},
+		CanDispel: true,
+	}).Create()
+}
+
+func Test_adjacentAttackAura(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+
+	ally := NewMinion("ally", 3, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p1, Unit: ally, Position: NewPosition(2, 2)})
+	enemy := NewMinion("enemy", 3, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: enemy, Position: NewPosition(3, 3)})
+
+	knight := newAuraMinion("knight", 3, 2, NearbyFriendlyMinions, Modifier{Attack: 1})
+	gs.MakeMove(&PlaceUnitAction{Owner: p1, Unit: knight, Position: NewPosition(2, 3)})
+
+	assert.Equal(t, 2, ally.GetAttack())
+	assert.Equal(t, 1, enemy.GetAttack())
+	assert.Equal(t, 2, knight.GetAttack())
+	// Generals are not minions
+	assert.Equal(t, 2, p1.GetGeneral().GetAttack())
+
+	endTurns(gs, p1, p2)
+	gs.MakeMove(&MoveAction{Unit: ally, Position: NewPosition(4, 1)})
+	assert.Equal(t, 1, ally.GetAttack())
+
+	endTurns(gs, p1, p2)
+	gs.MakeMove(&MoveAction{Unit: ally, Position: NewPosition(1, 3)})
+	assert.Equal(t, 2, ally.GetAttack())
+
+	// Dispelling the buffed unit does not escape the aura
+	gs.MakeMove(&DispelAction{Unit: ally})
+	assert.Equal(t, 2, ally.GetAttack())
+
+	gs.MakeMove(&DispelAction{Unit: knight})
+	assert.Equal(t, 1, ally.GetAttack())
+}
+
+func Test_auraEndsAfterDispel(t *testing.T) {
+	RegisterKeyword(KeywordDefinition{
+		Keyword:     "warded",
+		DisplayName: "Warded",
+		Dispellable: false,
+	})
+	defer UnregisterKeyword("warded")
+
+	p1, _, gs := setupGamestate()
+
+	ally := NewMinion("ally", 3, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p1, Unit: ally, Position: NewPosition(2, 2)})
+
+	warden := newAuraMinion("warden", 3, 1, NearbyFriendlyMinions, Modifier{
+		Attack:     1,
+		Attributes: map[Keyword]int{Provoke: 0, "warded": 0},
+	})
+	gs.MakeMove(&PlaceUnitAction{Owner: p1, Unit: warden, Position: NewPosition(3, 2)})
+	assert.Equal(t, 2, ally.GetAttack())
+	assert.True(t, ally.HasAttribute("warded"))
+
+	// Dispel strips the attack and provoke but not warded; the aura grants
+	// the rest again
+	gs.MakeMove(&DispelAction{Unit: ally})
+	assert.Equal(t, 2, ally.GetAttack())
+	assert.True(t, ally.HasAttribute(Provoke))
+	assert.True(t, ally.HasAttribute("warded"))
+
+	// When the aura ends it takes back everything it granted
+	gs.MakeMove(&RemoveUnitAction{Unit: warden})
+	assert.Equal(t, 1, ally.GetAttack())
+	assert.False(t, ally.HasAttribute(Provoke))
+	assert.False(t, ally.HasAttribute("warded"))
+}
+
+func Test_generalKeywordAura(t *testing.T) {
+	p1, _, gs := setupGamestate()
+	p1general := p1.GetGeneral()
+	p1general.AddAttribute("ranged", 0)
+
+	guardian := newAuraMinion("guardian", 2, 1, FriendlyGeneral, Modifier{
+		Attributes: map[Keyword]int{Provoke: 0, Ranged: 0},
+	})
+	gs.MakeMove(&PlaceUnitAction{Owner: p1, Unit: guardian, Position: NewPosition(5, 0)})
+
+	assert.True(t, p1general.HasAttribute("provoke"))
+
+	gs.MakeMove(&DamageAction{Unit: guardian, Damage: 2})
+
+	assert.False(t, guardian.IsAlive())
+	assert.False(t, p1general.HasAttribute("provoke"))
+	assert.True(t, p1general.HasAttribute("ranged"))
+}
+
+func Test_healthAuraEndingKills(t *testing.T) {
+	p1, _, gs := setupGamestate()
+
+	ally := NewMinion("ally", 1, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p1, Unit: ally, Position: NewPosition(2, 2)})
+
+	banner := newAuraMinion("banner", 2, 0, NearbyFriendlyMinions, Modifier{Health: 2})
+	gs.MakeMove(&PlaceUnitAction{Owner: p1, Unit: banner, Position: NewPosition(3, 2)})
+
+	assert.Equal(t, 3, ally.GetHp())
+	gs.MakeMove(&DamageAction{Unit: ally, Damage: 2})
+	assert.True(t, ally.IsAlive())
+
+	gs.MakeMove(&RemoveUnitAction{Unit: banner})
+	assert.False(t, ally.IsAlive())
+	assert.Equal(t, 1, len(p1.GetUnits()))
+}
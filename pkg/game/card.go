@@ -0,0 +1,68 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrUnknownCard = errors.New("unknown card")
+
+// CardID names a unit card in the card registry.
+type CardID string
+
+type registeredCard struct {
+	id      CardID
+	factory *UnitFactory
+}
+
+var cards = []registeredCard{}
+
+// RegisterCard adds a unit card, replacing any earlier card with the same ID.
+// Invalid definitions are rejected.
+func RegisterCard(id CardID, factory *UnitFactory) error {
+	if err := factory.Err(); err != nil {
+		return err
+	}
+	factory.card = id
+
+	for index, card := range cards {
+		if card.id == id {
+			cards[index].factory = factory
+			return nil
+		}
+	}
+
+	cards = append(cards, registeredCard{id: id, factory: factory})
+	return nil
+}
+
+// LookupCard returns the definition of a registered card.
+func LookupCard(id CardID) (*UnitFactory, bool) {
+	for _, card := range cards {
+		if card.id == id {
+			return card.factory, true
+		}
+	}
+
+	return nil, false
+}
+
+// CardIDs lists every registered card in registration order.
+func CardIDs() []CardID {
+	ids := make([]CardID, len(cards))
+	for index, card := range cards {
+		ids[index] = card.id
+	}
+
+	return ids
+}
+
+// CreateCard builds a fresh unit from a registered card.
+func CreateCard(id CardID) (Unit, error) {
+	factory, ok := LookupCard(id)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownCard, id)
+	}
+
+	return factory.Create(), nil
+}
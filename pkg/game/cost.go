@@ -0,0 +1,118 @@
+package game
+
+// Card is anything played from hand for mana.
+type Card interface {
+	GetCost() int
+}
+
+func SpellCards(card Card) bool {
+	_, ok := card.(Spell)
+	return ok
+}
+
+func ArtifactCards(card Card) bool {
+	_, ok := card.(*Artifact)
+	return ok
+}
+
+func MinionCards(card Card) bool {
+	_, ok := card.(*UnitFactory)
+	return ok
+}
+
+type CostMode int
+
+const (
+	// CostAdd adds Value to the cost; use a negative Value for discounts.
+	CostAdd CostMode = iota
+	// CostSet replaces the cost with Value.
+	CostSet
+)
+
+// CostModifier changes what a player pays for matching cards. Uses counts the
+// cards it applies to before it is used up and Turns counts the turn ends it
+// lasts; zero means it never runs out that way.
+type CostModifier struct {
+	Filter func(Card) bool
+	Mode   CostMode
+	Value  int
+	Uses   int
+	Turns  int
+}
+
+func (cm *CostModifier) matches(card Card) bool {
+	return cm.Filter == nil || cm.Filter(card)
+}
+
+func (cm *CostModifier) apply(cost int) int {
+	if cm.Mode == CostSet {
+		return cm.Value
+	}
+
+	return cost + cm.Value
+}
+
+// AddCostModifier registers a modifier. Modifiers apply in the order they were
+// added, so a later one can adjust a cost an earlier one set.
+func (p *Player) AddCostModifier(modifier *CostModifier) {
+	p.costModifiers = append(p.costModifiers, modifier)
+}
+
+func (p *Player) RemoveCostModifier(modifier *CostModifier) {
+	for index, existing := range p.costModifiers {
+		if existing == modifier {
+			p.costModifiers = append(p.costModifiers[:index], p.costModifiers[index+1:]...)
+			return
+		}
+	}
+}
+
+func (p *Player) CostModifiers() []*CostModifier {
+	return append([]*CostModifier{}, p.costModifiers...)
+}
+
+// EffectiveCost is what the player would pay for a card right now. It is
+// never below zero.
+func (p *Player) EffectiveCost(card Card) int {
+	cost := card.GetCost()
+	for _, modifier := range p.costModifiers {
+		if modifier.matches(card) {
+			cost = modifier.apply(cost)
+		}
+	}
+
+	return max(cost, 0)
+}
+
+// payForCard spends the card's effective cost and uses up the modifiers that
+// applied to it. It returns the mana paid.
+func (p *Player) payForCard(card Card) (int, bool) {
+	cost := p.EffectiveCost(card)
+	if !p.SpendMana(cost) {
+		return 0, false
+	}
+
+	for _, modifier := range p.CostModifiers() {
+		if modifier.Uses > 0 && modifier.matches(card) {
+			modifier.Uses--
+			if modifier.Uses == 0 {
+				p.RemoveCostModifier(modifier)
+			}
+		}
+	}
+
+	return cost, true
+}
+
+// expireCostModifiers counts down modifiers with a duration at the end of a
+// turn.
+func (p *Player) expireCostModifiers() {
+	for _, modifier := range p.CostModifiers() {
+		if modifier.Turns > 0 {
+			modifier.Turns--
+			if modifier.Turns == 0 {
+				p.RemoveCostModifier(modifier)
+			}
+		}
+	}
+}
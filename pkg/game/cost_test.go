@@ -0,0 +1,82 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/RGood/game_engine/pkg/gamestate"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_costModifiers(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+	p1.Mana = 9
+	p2.Mana = 9
+
+	spell := NewGenericSpell("Mist Meditation", 2, func(*Player, *gamestate.Gamestate, []Unit, []Position) {})
+	artifact := NewArtifact("Sunstone Bracers", 1)
+	minion := NewUnitFactory().SetName("Kaido Assassin").SetCost(2)
+
+	// Your next spell costs 1 less
+	p1.AddCostModifier(&CostModifier{Filter: SpellCards, Value: -1, Uses: 1})
+	// Minions cost 1 more this turn
+	p1.AddCostModifier(&CostModifier{Filter: MinionCards, Value: 1, Turns: 1})
+
+	assert.Equal(t, 1, p1.EffectiveCost(spell))
+	assert.Equal(t, 1, p1.EffectiveCost(artifact))
+	assert.Equal(t, 3, p1.EffectiveCost(minion))
+
+	assert.NoError(t, spell.Cast(p1, gs, nil, nil))
+	assert.Equal(t, 8, p1.Mana)
+	assert.Equal(t, 2, p1.EffectiveCost(spell))
+
+	gs.MakeMove(&EndTurnAction{Owner: p1})
+	assert.Equal(t, 2, p1.EffectiveCost(minion))
+	assert.Empty(t, p1.CostModifiers())
+
+	// Costs never drop below zero
+	p2.AddCostModifier(&CostModifier{Value: -5})
+	assert.Equal(t, 0, p2.EffectiveCost(spell))
+
+	// A later modifier adjusts a cost an earlier one set
+	p2.AddCostModifier(&CostModifier{Filter: ArtifactCards, Mode: CostSet, Value: 0})
+	p2.AddCostModifier(&CostModifier{Filter: ArtifactCards, Value: 2})
+	assert.Equal(t, 2, p2.EffectiveCost(artifact))
+
+	assert.NoError(t, artifact.Play(p2, gs))
+	assert.Equal(t, 7, p2.Mana)
+	assert.Equal(t, p2, artifact.Owner)
+}
+
+func Test_minionPlayPaysCost(t *testing.T) {
+	p1, _, gs := setupGamestate()
+	p1.Mana = 4
+
+	minion := NewUnitFactory().SetName("Kaido Assassin").SetUnitType("minion").SetHealth(3).SetAttack(2).SetCost(2)
+	p1.AddToHand(minion)
+
+	// Your next minion costs 1 less
+	discount := &CostModifier{Filter: MinionCards, Value: -1, Uses: 1}
+	p1.AddCostModifier(discount)
+
+	// Nothing is spent on a play that can't happen
+	_, err := minion.Play(p1, gs, p1.GetGeneral().GetPosition())
+	assert.ErrorIs(t, err, ErrNoSpace)
+	assert.Equal(t, 4, p1.Mana)
+
+	unit, err := minion.Play(p1, gs, NewPosition(1, 2))
+	assert.NoError(t, err)
+	assert.Equal(t, unit, p1.Board.Positions[NewPosition(1, 2)])
+	assert.Equal(t, p1, unit.GetOwner())
+	assert.Equal(t, 3, p1.Mana)
+	assert.Empty(t, p1.CostModifiers())
+	assert.Empty(t, p1.Hand())
+
+	// The discount was used up
+	_, err = minion.Play(p1, gs, NewPosition(1, 3))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, p1.Mana)
+
+	_, err = minion.Play(p1, gs, NewPosition(1, 1))
+	assert.ErrorIs(t, err, ErrInsufficientMana)
+	assert.False(t, p1.Board.IsOccupied(NewPosition(1, 1)))
+}
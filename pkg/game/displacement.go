@@ -0,0 +1,142 @@
+package game
+
+import "github.com/RGood/game_engine/pkg/gamestate"
+
+// ForcedMove is an action that moves units by effect rather than by walking.
+// Forced moves don't spend a unit's move or turn it around. Listeners can
+// look for ForcedMove to react to any of them; MoveAction is not one.
+type ForcedMove interface {
+	gamestate.Action
+	// MovedUnits lists the units that changed tiles once the action executed.
+	MovedUnits() []Unit
+}
+
+// SwapAction exchanges the tiles of two units at once.
+type SwapAction struct {
+	First  Unit
+	Second Unit
+	moved  bool
+}
+
+func (sa *SwapAction) Execute(gs *gamestate.Gamestate) *gamestate.Gamestate {
+	if board := sa.First.GetBoard(); board != nil && board == sa.Second.GetBoard() {
+		sa.moved = board.SwapUnits(sa.First, sa.Second)
+	}
+
+	return gs
+}
+
+func (sa *SwapAction) MovedUnits() []Unit {
+	if !sa.moved {
+		return []Unit{}
+	}
+
+	return []Unit{sa.First, sa.Second}
+}
+
+func (sa *SwapAction) AffectedUnits() []Unit {
+	return []Unit{sa.First, sa.Second}
+}
+
+// TeleportAction moves a unit straight to a tile. It fizzles if the tile is
+// off the board or taken.
+type TeleportAction struct {
+	Unit     Unit
+	Position Position
+	moved    bool
+}
+
+func (ta *TeleportAction) Execute(gs *gamestate.Gamestate) *gamestate.Gamestate {
+	if board := ta.Unit.GetBoard(); board != nil {
+		ta.moved = board.MoveUnit(ta.Unit, ta.Position)
+	}
+
+	return gs
+}
+
+func (ta *TeleportAction) MovedUnits() []Unit {
+	return movedIf(ta.moved, ta.Unit)
+}
+
+func (ta *TeleportAction) AffectedUnits() []Unit {
+	return []Unit{ta.Unit}
+}
+
+// PushAction moves a unit up to Distance tiles straight away from a tile,
+// including diagonally. It stops early at the board edge or another unit.
+type PushAction struct {
+	Unit     Unit
+	From     Position
+	Distance int
+	moved    bool
+}
+
+func (pa *PushAction) Execute(gs *gamestate.Gamestate) *gamestate.Gamestate {
+	diff := pa.Unit.GetPosition().Diff(pa.From)
+	pa.moved = slide(pa.Unit, NewPosition(sign(diff.X), sign(diff.Y)), pa.Distance, nil) > 0
+
+	return gs
+}
+
+func (pa *PushAction) MovedUnits() []Unit {
+	return movedIf(pa.moved, pa.Unit)
+}
+
+func (pa *PushAction) AffectedUnits() []Unit {
+	return []Unit{pa.Unit}
+}
+
+// PullAction moves a unit up to Distance tiles straight toward a tile,
+// stopping before it, at another unit, or at the board edge.
+type PullAction struct {
+	Unit     Unit
+	Toward   Position
+	Distance int
+	moved    bool
+}
+
+func (pa *PullAction) Execute(gs *gamestate.Gamestate) *gamestate.Gamestate {
+	diff := pa.Toward.Diff(pa.Unit.GetPosition())
+	stop := func(pos Position) bool {
+		return pos == pa.Toward
+	}
+	pa.moved = slide(pa.Unit, NewPosition(sign(diff.X), sign(diff.Y)), pa.Distance, stop) > 0
+
+	return gs
+}
+
+func (pa *PullAction) MovedUnits() []Unit {
+	return movedIf(pa.moved, pa.Unit)
+}
+
+func (pa *PullAction) AffectedUnits() []Unit {
+	return []Unit{pa.Unit}
+}
+
+// slide steps a unit along a direction until it has gone distance tiles or
+// the next tile is blocked, returning how far it went.
+func slide(unit Unit, direction Position, distance int, stop func(Position) bool) int {
+	board := unit.GetBoard()
+	if board == nil || direction == NewPosition(0, 0) {
+		return 0
+	}
+
+	steps := 0
+	for steps < distance {
+		next := unit.GetPosition().Add(direction)
+		if (stop != nil && stop(next)) || !board.MoveUnit(unit, next) {
+			break
+		}
+		steps++
+	}
+
+	return steps
+}
+
+func movedIf(moved bool, unit Unit) []Unit {
+	if !moved {
+		return []Unit{}
+	}
+
+	return []Unit{unit}
+}
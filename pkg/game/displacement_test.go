@@ -0,0 +1,76 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/RGood/game_engine/pkg/gamestate"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_swap(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+
+	gremlin := NewMinion("gremlin", 1, 1)
+	goblin := NewMinion("goblin", 1, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p1, Unit: gremlin, Position: NewPosition(1, 2)})
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: goblin, Position: NewPosition(7, 2)})
+	endTurns(gs, p1, p2)
+
+	forced := [][]Unit{}
+	NewUntilEndOfTurnListener(func(action gamestate.Action, _ *gamestate.Gamestate) bool {
+		_, ok := action.(ForcedMove)
+		return ok
+	}, func(_ gamestate.Listener, action gamestate.Action, _ *gamestate.Gamestate) {
+		forced = append(forced, action.(ForcedMove).MovedUnits())
+	}).Subscribe(gs)
+
+	gs.MakeMove(&SwapAction{First: gremlin, Second: goblin})
+
+	assert.Equal(t, NewPosition(7, 2), gremlin.GetPosition())
+	assert.Equal(t, NewPosition(1, 2), goblin.GetPosition())
+	assert.Equal(t, gremlin, p1.Board.Positions[NewPosition(7, 2)])
+	assert.Equal(t, goblin, p1.Board.Positions[NewPosition(1, 2)])
+	// Swapping is not walking
+	assert.True(t, gremlin.FacesRight())
+
+	gs.MakeMove(&MoveAction{Unit: gremlin, Position: NewPosition(7, 3)})
+	assert.Equal(t, NewPosition(7, 3), gremlin.GetPosition())
+	assert.Equal(t, [][]Unit{{gremlin, goblin}}, forced)
+}
+
+func Test_pushPullTeleport(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+	p1general := p1.GetGeneral()
+
+	target := NewMinion("target", 3, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: target, Position: NewPosition(1, 2)})
+	blocker := NewMinion("blocker", 3, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: blocker, Position: NewPosition(4, 2)})
+
+	// Pushed away from the general until another unit is in the way
+	push := &PushAction{Unit: target, From: p1general.GetPosition(), Distance: 5}
+	gs.MakeMove(push)
+	assert.Equal(t, NewPosition(3, 2), target.GetPosition())
+	assert.Equal(t, []Unit{target}, push.MovedUnits())
+
+	// Pushed diagonally into the board edge
+	gs.MakeMove(&PushAction{Unit: target, From: NewPosition(2, 1), Distance: 5})
+	assert.Equal(t, NewPosition(5, 4), target.GetPosition())
+
+	// Pulled up to the tile in front of the general
+	gs.MakeMove(&PullAction{Unit: blocker, Toward: p1general.GetPosition(), Distance: 9})
+	assert.Equal(t, NewPosition(1, 2), blocker.GetPosition())
+
+	blocked := &PullAction{Unit: blocker, Toward: p1general.GetPosition(), Distance: 1}
+	gs.MakeMove(blocked)
+	assert.Empty(t, blocked.MovedUnits())
+
+	// Teleports only land on free tiles
+	gs.MakeMove(&TeleportAction{Unit: target, Position: NewPosition(8, 2)})
+	assert.Equal(t, NewPosition(5, 4), target.GetPosition())
+	gs.MakeMove(&TeleportAction{Unit: target, Position: NewPosition(9, 2)})
+	assert.Equal(t, NewPosition(5, 4), target.GetPosition())
+	gs.MakeMove(&TeleportAction{Unit: target, Position: NewPosition(8, 0)})
+	assert.Equal(t, NewPosition(8, 0), target.GetPosition())
+	assert.Equal(t, 4, len(p1.Board.Positions))
+}
@@ -0,0 +1,367 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrUnknownKeyword = errors.New("unknown keyword")
+	ErrKeywordValue   = errors.New("keyword does not take a value")
+)
+
+// Keyword names a unit ability. Every keyword a unit has must be registered
+// with RegisterKeyword first.
+type Keyword string
+
+const (
+	Ranged   Keyword = "ranged"
+	Blast    Keyword = "blast"
+	Backstab Keyword = "backstab"
+	Frenzy   Keyword = "frenzy"
+	Provoke  Keyword = "provoke"
+	Stunned  Keyword = "stunned"
+	Celerity Keyword = "celerity"
+	Rush     Keyword = "rush"
+)
+
+// StackingRule decides the value of a keyword a unit gains twice.
+type StackingRule int
+
+const (
+	// StackReplace keeps the newest value.
+	StackReplace StackingRule = iota
+	// StackSum adds the values together.
+	StackSum
+	// StackMax keeps the higher value.
+	StackMax
+)
+
+func (sr StackingRule) combine(current int, value int) int {
+	switch sr {
+	case StackSum:
+		return current + value
+	case StackMax:
+		if current > value {
+			return current
+		}
+	}
+
+	return value
+}
+
+// KeywordDefinition describes a keyword. Range extends the reach of units with
+// the keyword, and Handler may implement any of the hook interfaces below.
+type KeywordDefinition struct {
+	Keyword     Keyword
+	DisplayName string
+	Description string
+	HasValue    bool
+	Dispellable bool
+	Stacking    StackingRule
+	Range       RangeProfile
+	Handler     interface{}
+}
+
+// KeywordHandler gives a keyword its behaviour. A handler implements any of
+// the hook interfaces below; each hook runs for units that have the keyword.
+type KeywordHandler interface {
+	Keyword() Keyword
+}
+
+// TurnBudgetHook changes how many actions (a move followed by an attack) a
+// unit gets each turn.
+type TurnBudgetHook interface {
+	ActionsPerTurn(unit Unit, actions int) int
+}
+
+// SummonHook runs when a unit is summoned, after it has been exhausted.
+type SummonHook interface {
+	OnSummon(unit Unit)
+}
+
+// AttackHook adds collateral damage to an attack. damage already holds the
+// hit on the defender, which hooks should leave alone.
+type AttackHook interface {
+	OnAttack(attacker Unit, defender Unit, damage map[Unit]int)
+}
+
+var keywords = []*KeywordDefinition{}
+
+// RegisterKeyword adds a keyword, replacing any earlier definition of it.
+// Hooks run in registration order.
+func RegisterKeyword(definition KeywordDefinition) {
+	for index, existing := range keywords {
+		if existing.Keyword == definition.Keyword {
+			keywords[index] = &definition
+			return
+		}
+	}
+
+	keywords = append(keywords, &definition)
+}
+
+// UnregisterKeyword removes a keyword and its hooks. Units that already have
+// it keep it, but it no longer does anything for them.
+func UnregisterKeyword(keyword Keyword) {
+	for index, existing := range keywords {
+		if existing.Keyword == keyword {
+			keywords = append(keywords[:index], keywords[index+1:]...)
+			return
+		}
+	}
+}
+
+// LookupKeyword returns the definition of a registered keyword.
+func LookupKeyword(keyword Keyword) (KeywordDefinition, bool) {
+	if definition := findKeyword(keyword); definition != nil {
+		return *definition, true
+	}
+
+	return KeywordDefinition{}, false
+}
+
+// Keywords lists every registered keyword in registration order.
+func Keywords() []KeywordDefinition {
+	definitions := make([]KeywordDefinition, len(keywords))
+	for index, definition := range keywords {
+		definitions[index] = *definition
+	}
+
+	return definitions
+}
+
+func findKeyword(keyword Keyword) *KeywordDefinition {
+	for _, definition := range keywords {
+		if definition.Keyword == keyword {
+			return definition
+		}
+	}
+
+	return nil
+}
+
+// defineKeyword finds a keyword, registering a plain dispellable one if it is
+// new, so hooks and ranges can be attached to keywords defined elsewhere.
+func defineKeyword(keyword Keyword) *KeywordDefinition {
+	if definition := findKeyword(keyword); definition != nil {
+		return definition
+	}
+
+	RegisterKeyword(KeywordDefinition{
+		Keyword:     keyword,
+		DisplayName: string(keyword),
+		Dispellable: true,
+	})
+
+	return findKeyword(keyword)
+}
+
+// RegisterKeywordHandler sets the behaviour of a keyword, replacing any
+// earlier handler for it.
+func RegisterKeywordHandler(handler KeywordHandler) {
+	defineKeyword(handler.Keyword()).Handler = handler
+}
+
+// checkKeyword reports whether a unit may be given the keyword with the value.
+func checkKeyword(keyword Keyword, value int) error {
+	definition := findKeyword(keyword)
+	if definition == nil {
+		return fmt.Errorf("%w: %q", ErrUnknownKeyword, keyword)
+	}
+
+	if !definition.HasValue && value != 0 {
+		return fmt.Errorf("%w: %q", ErrKeywordValue, keyword)
+	}
+
+	return nil
+}
+
+// addKeyword gives the keyword to a set of attributes, stacking it with any
+// value already there. Unknown keywords are left out and reported.
+func addKeyword(attributes map[Keyword]int, keyword Keyword, value int) error {
+	if err := checkKeyword(keyword, value); err != nil {
+		return err
+	}
+
+	if current, ok := attributes[keyword]; ok {
+		value = findKeyword(keyword).Stacking.combine(current, value)
+	}
+
+	attributes[keyword] = value
+	return nil
+}
+
+// isDispellable reports whether Dispel removes the keyword. Unknown keywords
+// are never kept.
+func isDispellable(keyword Keyword) bool {
+	definition := findKeyword(keyword)
+	return definition == nil || definition.Dispellable
+}
+
+// handlersFor lists the hooks for the keywords a unit has.
+func handlersFor(unit Unit) []interface{} {
+	handlers := []interface{}{}
+	for _, definition := range keywords {
+		if definition.Handler != nil && unit.HasAttribute(definition.Keyword) {
+			handlers = append(handlers, definition.Handler)
+		}
+	}
+
+	return handlers
+}
+
+func actionsPerTurn(unit Unit) int {
+	actions := 1
+	for _, handler := range handlersFor(unit) {
+		if hook, ok := handler.(TurnBudgetHook); ok {
+			actions = hook.ActionsPerTurn(unit, actions)
+		}
+	}
+
+	return actions
+}
+
+func onSummon(unit Unit) {
+	for _, handler := range handlersFor(unit) {
+		if hook, ok := handler.(SummonHook); ok {
+			hook.OnSummon(unit)
+		}
+	}
+}
+
+func onAttack(attacker Unit, defender Unit, damage map[Unit]int) {
+	for _, handler := range handlersFor(attacker) {
+		if hook, ok := handler.(AttackHook); ok {
+			hook.OnAttack(attacker, defender, damage)
+		}
+	}
+}
+
+// Celerity: the unit can move and attack twice each turn.
+type celerityHandler struct{}
+
+func (celerityHandler) Keyword() Keyword {
+	return Celerity
+}
+
+func (celerityHandler) ActionsPerTurn(unit Unit, actions int) int {
+	return actions + 1
+}
+
+// Rush: the unit can act the turn it is summoned.
+type rushHandler struct{}
+
+func (rushHandler) Keyword() Keyword {
+	return Rush
+}
+
+func (rushHandler) OnSummon(unit Unit) {
+	unit.Refresh()
+}
+
+// Frenzy: attacking an adjacent unit hits every adjacent enemy.
+type frenzyHandler struct{}
+
+func (frenzyHandler) Keyword() Keyword {
+	return Frenzy
+}
+
+func (frenzyHandler) OnAttack(attacker Unit, defender Unit, damage map[Unit]int) {
+	if !attacker.IsNear(defender) {
+		return
+	}
+
+	board := attacker.GetBoard()
+	for _, unit := range filterUnits(board.UnitsIn(board.NearbyTiles(attacker.GetPosition())), attacker.IsEnemy) {
+		if unit != defender {
+			damage[unit] = attacker.GetAttack()
+		}
+	}
+}
+
+// Blast: attacks hit every enemy in the row or column the attacker shares
+// with the defender, on either side of the attacker.
+type blastHandler struct{}
+
+func (blastHandler) Keyword() Keyword {
+	return Blast
+}
+
+func (blastHandler) OnAttack(attacker Unit, defender Unit, damage map[Unit]int) {
+	if isInline, _ := attacker.IsInline(defender); !isInline {
+		return
+	}
+
+	board := attacker.GetBoard()
+	from := attacker.GetPosition()
+	line := board.ColumnTiles(from.X)
+	if defender.GetPosition().Y == from.Y {
+		line = board.RowTiles(from.Y)
+	}
+
+	for _, unit := range filterUnits(board.UnitsIn(line), attacker.IsEnemy) {
+		if unit != defender {
+			damage[unit] = attacker.GetAttack()
+		}
+	}
+}
+
+func init() {
+	RegisterKeyword(KeywordDefinition{
+		Keyword:     Ranged,
+		DisplayName: "Ranged",
+		Description: "Can attack any enemy on the board.",
+		Dispellable: true,
+		Range:       RangedRange,
+	})
+	RegisterKeyword(KeywordDefinition{
+		Keyword:     Blast,
+		DisplayName: "Blast",
+		Description: "Attacks hit every enemy in a row or column.",
+		Dispellable: true,
+		Range:       BlastRange,
+		Handler:     blastHandler{},
+	})
+	RegisterKeyword(KeywordDefinition{
+		Keyword:     Backstab,
+		DisplayName: "Backstab",
+		Description: "Deals extra damage when attacking from behind, and takes no counterattack.",
+		HasValue:    true,
+		Dispellable: true,
+		Stacking:    StackSum,
+	})
+	RegisterKeyword(KeywordDefinition{
+		Keyword:     Frenzy,
+		DisplayName: "Frenzy",
+		Description: "Attacks hit every nearby enemy.",
+		Dispellable: true,
+		Handler:     frenzyHandler{},
+	})
+	RegisterKeyword(KeywordDefinition{
+		Keyword:     Provoke,
+		DisplayName: "Provoke",
+		Description: "Nearby enemies must attack this unit first.",
+		Dispellable: true,
+	})
+	RegisterKeyword(KeywordDefinition{
+		Keyword:     Stunned,
+		DisplayName: "Stunned",
+		Description: "Cannot counterattack.",
+		Dispellable: true,
+	})
+	RegisterKeyword(KeywordDefinition{
+		Keyword:     Celerity,
+		DisplayName: "Celerity",
+		Description: "Can move and attack twice each turn.",
+		Dispellable: true,
+		Handler:     celerityHandler{},
+	})
+	RegisterKeyword(KeywordDefinition{
+		Keyword:     Rush,
+		DisplayName: "Rush",
+		Description: "Can act the turn it is summoned.",
+		Dispellable: true,
+		Handler:     rushHandler{},
+	})
+}
@@ -0,0 +1,209 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_frenzyHitsAdjacentEnemies(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+	p1general := p1.GetGeneral()
+	p1general.AddAttribute("frenzy", 0)
+
+	target := NewMinion("target", 3, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: target, Position: NewPosition(0, 1)})
+	diagonal := NewMinion("diagonal", 3, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: diagonal, Position: NewPosition(1, 3)})
+	friend := NewMinion("friend", 3, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p1, Unit: friend, Position: NewPosition(1, 2)})
+	distant := NewMinion("distant", 3, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: distant, Position: NewPosition(2, 2)})
+
+	gs.MakeMove(&AttackAction{Attacker: p1general, Defender: target})
+
+	assert.Equal(t, 1, target.GetHp())
+	assert.Equal(t, 1, diagonal.GetHp())
+	assert.Equal(t, 3, friend.GetHp())
+	assert.Equal(t, 3, distant.GetHp())
+	// Only the defender strikes back
+	assert.Equal(t, 24, p1general.GetHp())
+}
+
+func Test_blastHitsColumn(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+
+	cannon := NewMinion("cannon", 3, 2)
+	cannon.AddAttribute("blast", 0)
+	gs.MakeMove(&PlaceUnitAction{Owner: p1, Unit: cannon, Position: NewPosition(4, 1)})
+
+	target := NewMinion("target", 3, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: target, Position: NewPosition(4, 2)})
+	friend := NewMinion("friend", 3, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p1, Unit: friend, Position: NewPosition(4, 3)})
+	behind := NewMinion("behind", 3, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: behind, Position: NewPosition(4, 4)})
+	aside := NewMinion("aside", 3, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: aside, Position: NewPosition(5, 1)})
+	// The column runs through the attacker, so enemies on its far side are hit too
+	farSide := NewMinion("farSide", 3, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: farSide, Position: NewPosition(4, 0)})
+
+	endTurns(gs, p1, p2)
+	gs.MakeMove(&AttackAction{Attacker: cannon, Defender: target})
+
+	assert.Equal(t, 1, target.GetHp())
+	assert.Equal(t, 1, behind.GetHp())
+	assert.Equal(t, 1, farSide.GetHp())
+	assert.Equal(t, 3, friend.GetHp())
+	assert.Equal(t, 3, aside.GetHp())
+}
+
+func Test_celerity(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+	p1general := p1.GetGeneral()
+	p2general := p2.GetGeneral()
+
+	assert.Equal(t, 1, p1general.ActionsPerTurn())
+	p1general.AddAttribute("celerity", 0)
+	assert.Equal(t, 2, p1general.ActionsPerTurn())
+
+	gs.MakeMove(&EndTurnAction{Owner: p1})
+	gs.MakeMove(&MoveAction{Unit: p2general, Position: NewPosition(3, 2)})
+	gs.MakeMove(&EndTurnAction{Owner: p2})
+
+	gs.MakeMove(&MoveAction{Unit: p1general, Position: NewPosition(2, 2)})
+	assert.False(t, p1general.CanMove())
+	assert.True(t, p1general.CanAttack())
+
+	// A second move before attacking is refused
+	gs.MakeMove(&MoveAction{Unit: p1general, Position: NewPosition(1, 2)})
+	assert.Equal(t, NewPosition(2, 2), p1general.GetPosition())
+
+	gs.MakeMove(&AttackAction{Attacker: p1general, Defender: p2general})
+	assert.Equal(t, 23, p2general.GetHp())
+
+	// A second action: move again, then attack again
+	assert.True(t, p1general.CanMove())
+	gs.MakeMove(&MoveAction{Unit: p1general, Position: NewPosition(2, 3)})
+	gs.MakeMove(&AttackAction{Attacker: p1general, Defender: p2general})
+	assert.Equal(t, 21, p2general.GetHp())
+	assert.True(t, p1general.IsExhausted())
+
+	// With both actions spent, further moves and attacks are refused
+	gs.MakeMove(&AttackAction{Attacker: p1general, Defender: p2general})
+	gs.MakeMove(&MoveAction{Unit: p1general, Position: NewPosition(2, 4)})
+	assert.Equal(t, 21, p2general.GetHp())
+	assert.Equal(t, NewPosition(2, 3), p1general.GetPosition())
+
+	// So are attacks out of turn
+	assert.True(t, p2general.CanAttack())
+	gs.MakeMove(&AttackAction{Attacker: p2general, Defender: p1general})
+	assert.Equal(t, 21, p1general.GetHp())
+
+	// Without celerity, attacking ends the turn
+	gs.MakeMove(&EndTurnAction{Owner: p1})
+	gs.MakeMove(&AttackAction{Attacker: p2general, Defender: p1general})
+	assert.Equal(t, 19, p1general.GetHp())
+	assert.False(t, p2general.CanMove())
+	assert.False(t, p2general.CanAttack())
+
+	gs.MakeMove(&AttackAction{Attacker: p2general, Defender: p1general})
+	assert.Equal(t, 19, p1general.GetHp())
+}
+
+func Test_turnBudget(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+	p2general := p2.GetGeneral()
+
+	// Units cannot move on their opponent's turn
+	gs.MakeMove(&MoveAction{Unit: p2general, Position: NewPosition(7, 2)})
+	assert.Equal(t, NewPosition(8, 2), p2general.GetPosition())
+
+	// Or in the turn they are summoned
+	gremlin := NewMinion("gremlin", 3, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p1, Unit: gremlin, Position: NewPosition(7, 1)})
+	gs.MakeMove(&MoveAction{Unit: gremlin, Position: NewPosition(6, 1)})
+	gs.MakeMove(&AttackAction{Attacker: gremlin, Defender: p2general})
+	assert.Equal(t, NewPosition(7, 1), gremlin.GetPosition())
+	assert.Equal(t, 25, p2general.GetHp())
+
+	// Attacking spends the move too
+	gs.MakeMove(&EndTurnAction{Owner: p1})
+	gs.MakeMove(&EndTurnAction{Owner: p2})
+	gs.MakeMove(&AttackAction{Attacker: gremlin, Defender: p2general})
+	gs.MakeMove(&MoveAction{Unit: gremlin, Position: NewPosition(6, 1)})
+	assert.Equal(t, 24, p2general.GetHp())
+	assert.Equal(t, NewPosition(7, 1), gremlin.GetPosition())
+}
+
+func Test_rush(t *testing.T) {
+	p1, _, gs := setupGamestate()
+
+	slow := NewMinion("slow", 1, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p1, Unit: slow, Position: NewPosition(2, 2)})
+	fast := NewMinion("fast", 1, 1)
+	fast.AddAttribute("rush", 0)
+	gs.MakeMove(&PlaceUnitAction{Owner: p1, Unit: fast, Position: NewPosition(3, 2)})
+
+	assert.True(t, slow.IsExhausted())
+	assert.False(t, fast.IsExhausted())
+	assert.True(t, fast.CanMove())
+	assert.True(t, fast.CanAttack())
+}
+
+func Test_factoryRejectsUnknownKeywords(t *testing.T) {
+	factory := NewUnitFactory().SetName("typo").AddAttribute("frenzzy", 0)
+	assert.ErrorIs(t, factory.Err(), ErrUnknownKeyword)
+	assert.Panics(t, func() { factory.Create() })
+
+	factory = NewUnitFactory().SetName("valued").AddAttribute(Provoke, 2)
+	assert.ErrorIs(t, factory.Err(), ErrKeywordValue)
+
+	factory = NewUnitFactory().SetName("assassin").AddAttribute(Backstab, 2).AddAttribute(Provoke, 0)
+	assert.NoError(t, factory.Err())
+	unit := factory.Create()
+	assert.Equal(t, 2, unit.GetAttributeValue(Backstab))
+	assert.True(t, unit.HasAttribute(Provoke))
+
+	// Units don't share the factory's keywords
+	unit.RemoveAttribute(Provoke)
+	assert.True(t, factory.Create().HasAttribute(Provoke))
+}
+
+func Test_unknownKeywordsAtRuntime(t *testing.T) {
+	unit := NewMinion("scout", 2, 2)
+
+	assert.NotPanics(t, func() {
+		assert.ErrorIs(t, unit.AddAttribute("frenzzy", 0), ErrUnknownKeyword)
+		assert.ErrorIs(t, unit.AddAttribute(Provoke, 2), ErrKeywordValue)
+	})
+	assert.False(t, unit.HasAttribute("frenzzy"))
+	assert.False(t, unit.HasAttribute(Provoke))
+	assert.NoError(t, unit.AddAttribute(Provoke, 0))
+}
+
+func Test_keywordStackingAndDispel(t *testing.T) {
+	RegisterKeyword(KeywordDefinition{
+		Keyword:     "warded",
+		DisplayName: "Warded",
+		Dispellable: false,
+	})
+	defer UnregisterKeyword("warded")
+
+	unit := NewMinion("assassin", 2, 2)
+	unit.AddAttribute(Backstab, 2)
+	unit.AddAttribute(Backstab, 1)
+	assert.Equal(t, 3, unit.GetAttributeValue(Backstab))
+
+	unit.AddAttribute("warded", 0)
+	unit.Dispel()
+	assert.False(t, unit.HasAttribute(Backstab))
+	assert.True(t, unit.HasAttribute("warded"))
+
+	definition, ok := LookupKeyword(Celerity)
+	assert.True(t, ok)
+	assert.Equal(t, "Celerity", definition.DisplayName)
+	_, ok = LookupKeyword("frenzzy")
+	assert.False(t, ok)
+}
@@ -59,3 +59,97 @@ func (eot *UntilEndOfTurnListener) Notify(action gamestate.Action, game *gamesta
 		game.Unsubscribe(eot)
 	}
 }
+
+// UnitView is a copy of the parts of a unit that actions change, taken so a
+// listener can compare a unit before and after an action.
+type UnitView struct {
+	Unit     Unit
+	Hp       int
+	Attack   int
+	Position Position
+	Alive    bool
+}
+
+func ViewUnit(unit Unit) UnitView {
+	return UnitView{
+		Unit:     unit,
+		Hp:       unit.GetHp(),
+		Attack:   unit.GetAttack(),
+		Position: unit.GetPosition(),
+		Alive:    unit.IsAlive(),
+	}
+}
+
+// AffectedUnits lists the units an action acts on, or nil if the action does
+// not say.
+func AffectedUnits(action gamestate.Action) []Unit {
+	affected, ok := action.(interface{ AffectedUnits() []Unit })
+	if !ok {
+		return nil
+	}
+
+	return affected.AffectedUnits()
+}
+
+func viewAffected(action gamestate.Action) []UnitView {
+	views := []UnitView{}
+	for _, unit := range AffectedUnits(action) {
+		views = append(views, ViewUnit(unit))
+	}
+
+	return views
+}
+
+// PhaseListener observes actions on both sides of their execution. Either
+// hook may be nil. The affected units are taken once, before the action
+// executes, so the after hook receives the views captured then alongside
+// fresh views of the same units in the same order, even if the action's
+// affected units change as it executes.
+type PhaseListener struct {
+	beforeExecute func(gamestate.Action, []UnitView, *gamestate.Gamestate)
+	afterExecute  func(gamestate.Action, []UnitView, []UnitView, *gamestate.Gamestate)
+	pending       map[gamestate.Action][]UnitView
+}
+
+func NewPhaseListener(before func(gamestate.Action, []UnitView, *gamestate.Gamestate), after func(gamestate.Action, []UnitView, []UnitView, *gamestate.Gamestate)) *PhaseListener {
+	return &PhaseListener{
+		beforeExecute: before,
+		afterExecute:  after,
+		pending:       map[gamestate.Action][]UnitView{},
+	}
+}
+
+func (pl *PhaseListener) Subscribe(game *gamestate.Gamestate) {
+	game.Subscribe(pl)
+}
+
+func (pl *PhaseListener) Unsubscribe(game *gamestate.Gamestate) {
+	game.Unsubscribe(pl)
+}
+
+func (pl *PhaseListener) BeforeExecute(action gamestate.Action, game *gamestate.Gamestate) {
+	views := viewAffected(action)
+	pl.pending[action] = views
+
+	if pl.beforeExecute != nil {
+		pl.beforeExecute(action, views, game)
+	}
+}
+
+func (pl *PhaseListener) Notify(action gamestate.Action, game *gamestate.Gamestate) {
+	before, ok := pl.pending[action]
+	if !ok {
+		// Cancelled actions skip BeforeExecute
+		return
+	}
+	delete(pl.pending, action)
+
+	if pl.afterExecute != nil {
+		after := make([]UnitView, len(before))
+		for index, view := range before {
+			after[index] = ViewUnit(view.Unit)
+		}
+
+		pl.afterExecute(action, before, after, game)
+	}
+}
@@ -1,22 +1,59 @@
 package game
 
+import (
+	"encoding/json"
+
+	"github.com/RGood/game_engine/pkg/gamestate"
+)
+
 type Player struct {
-	id          *string
+	id          gamestate.EntityID
+	Name        string
 	General     string
 	StartingPos Position
 	FacesRight  bool
 	Board       *UnitBoard
+	Mana        int
+	Ability     *GeneralAbility
+
+	abilityCooldown int
+	costModifiers   []*CostModifier
+	artifacts       []*Artifact
+	hand            []Card
+}
+
+// StartingMana is the mana a new player has, as in Duelyst. Games that ramp
+// mana each turn set Player.Mana themselves.
+const StartingMana = 2
+
+// GeneralDefinition describes a general and its Bloodborn ability, if any.
+type GeneralDefinition struct {
+	Name    string
+	Health  int
+	Attack  int
+	Ability *GeneralAbility
+}
+
+func NewPlayer(name string, general string, board *UnitBoard, pos Position, right bool) *Player {
+	return NewPlayerWithGeneral(name, GeneralDefinition{
+		Name:   general,
+		Health: 25,
+		Attack: 2,
+	}, board, pos, right)
 }
 
-func NewPlayer(id string, general string, board *UnitBoard, pos Position, right bool) *Player {
+func NewPlayerWithGeneral(name string, general GeneralDefinition, board *UnitBoard, pos Position, right bool) *Player {
 	player := &Player{
-		id:         &id,
-		General:    general,
+		Name:       name,
+		General:    general.Name,
 		FacesRight: right,
 		Board:      board,
+		Mana:       StartingMana,
+		Ability:    general.Ability,
 	}
+	player.id = board.NewID(player)
 
-	generalUnit := NewUnitFactory().SetName(general).SetHealth(25).SetAttack(2).SetUnitType("general").Create()
+	generalUnit := NewUnitFactory().SetName(general.Name).SetHealth(general.Health).SetAttack(general.Attack).SetUnitType("general").Create()
 	generalUnit.Place(player, pos)
 
 	board.PlaceUnit(generalUnit, pos)
@@ -24,6 +61,46 @@ func NewPlayer(id string, general string, board *UnitBoard, pos Position, right
 	return player
 }
 
+// GetID is zero for a nil player, so unowned entities can write their owner.
+func (p *Player) GetID() gamestate.EntityID {
+	if p == nil {
+		return 0
+	}
+
+	return p.id
+}
+
+// playerJSON is how a player is serialized. Units reference players by ID,
+// while the player writes out the cards it holds.
+type playerJSON struct {
+	ID              gamestate.EntityID
+	Name            string
+	General         string
+	FacesRight      bool
+	Mana            int
+	AbilityCooldown int
+	Artifacts       []*Artifact
+	Hand            []Card
+}
+
+func (p *Player) MarshalJSON() ([]byte, error) {
+	return json.Marshal(playerJSON{
+		ID:              p.id,
+		Name:            p.Name,
+		General:         p.General,
+		FacesRight:      p.FacesRight,
+		Mana:            p.Mana,
+		AbilityCooldown: p.abilityCooldown,
+		Artifacts:       p.Artifacts(),
+		Hand:            p.Hand(),
+	})
+}
+
+// Lookup makes a player a gamestate.Directory for everything on its board.
+func (p *Player) Lookup(id gamestate.EntityID) (interface{}, bool) {
+	return p.Board.Lookup(id)
+}
+
 func (p *Player) IsAlive() bool {
 	for unit, _ := range p.Board.Units {
 		if unit.GetOwner() == p && unit.GetType() == "general" {
@@ -56,3 +133,123 @@ func (p *Player) GetGeneral() Unit {
 
 	return nil
 }
+
+// Artifacts lists the player's equipped artifacts, oldest first.
+func (p *Player) Artifacts() []*Artifact {
+	return append([]*Artifact{}, p.artifacts...)
+}
+
+func (p *Player) removeArtifact(artifact *Artifact) {
+	for index, existing := range p.artifacts {
+		if existing == artifact {
+			p.artifacts = append(p.artifacts[:index], p.artifacts[index+1:]...)
+			return
+		}
+	}
+}
+
+// cardEntity is a card that takes an entity ID as it enters a zone. Minion
+// cards have none; the unit they create takes one as it enters play.
+type cardEntity interface {
+	assignID(ub *UnitBoard)
+}
+
+func (p *Player) enterZone(card Card) {
+	if entity, ok := card.(cardEntity); ok {
+		entity.assignID(p.Board)
+	}
+}
+
+// AddToHand puts a card in the player's hand, giving it an ID so that it can
+// be referred to before it is played.
+func (p *Player) AddToHand(card Card) {
+	p.enterZone(card)
+	p.hand = append(p.hand, card)
+}
+
+// Hand lists the cards in the player's hand, oldest first.
+func (p *Player) Hand() []Card {
+	return append([]Card{}, p.hand...)
+}
+
+// leaveHand takes a card out of the hand as it is played. Cards played from
+// elsewhere are left alone.
+func (p *Player) leaveHand(card Card) {
+	for index, existing := range p.hand {
+		if existing == card {
+			p.hand = append(p.hand[:index], p.hand[index+1:]...)
+			return
+		}
+	}
+}
+
+// isActive reports whether it is the player's turn.
+func (p *Player) isActive(gs *gamestate.Gamestate) bool {
+	return gs.ActivePlayer == gamestate.Player(p)
+}
+
+// canPlay reports whether the player may play a card or use their ability:
+// the game must still be running, no prompt may be waiting for an answer and
+// it must be the player's turn.
+func (p *Player) canPlay(gs *gamestate.Gamestate) error {
+	if gs.HasEnded() || gs.PendingPrompt() != nil {
+		return ErrCannotCast
+	}
+
+	if !p.isActive(gs) {
+		return ErrNotYourTurn
+	}
+
+	return nil
+}
+
+func (p *Player) SpendMana(cost int) bool {
+	if cost > p.Mana {
+		return false
+	}
+
+	p.Mana -= cost
+	return true
+}
+
+func (p *Player) GainMana(mana int) {
+	p.Mana += mana
+}
+
+// PlayerState is what a snapshot keeps for a player: its mana, ability
+// cooldown and the tile entities on its board, all as plain values. Units are
+// not part of it, so restoring a snapshot leaves their health, buffs and
+// positions as they are.
+type PlayerState struct {
+	Mana            int
+	AbilityCooldown int
+	Tiles           []TileState
+}
+
+// SaveState makes players gamestate.Stateful. Players sharing a board both
+// save its tiles; loading them twice is harmless.
+func (p *Player) SaveState() interface{} {
+	tiles := []TileState{}
+	for _, tile := range p.Board.SortedTiles() {
+		tiles = append(tiles, tile.State())
+	}
+
+	return PlayerState{
+		Mana:            p.Mana,
+		AbilityCooldown: p.abilityCooldown,
+		Tiles:           tiles,
+	}
+}
+
+// LoadState restores a saved PlayerState, rebuilding the board's tiles from
+// their saved values.
+func (p *Player) LoadState(state interface{}) {
+	saved := state.(PlayerState)
+	p.Mana = saved.Mana
+	p.abilityCooldown = saved.AbilityCooldown
+
+	p.Board.tiles = map[Position]*Tile{}
+	for _, tileState := range saved.Tiles {
+		p.Board.tiles[tileState.Position] = p.Board.restoreTile(tileState)
+	}
+}
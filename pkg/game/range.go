@@ -0,0 +1,74 @@
+package game
+
+// RangeProfile decides which units an attacker can reach. It is consulted for
+// attacks, valid target listing and counterattacks.
+type RangeProfile interface {
+	InRange(attacker Unit, target Unit) bool
+}
+
+// RangeFunc adapts a plain function to a RangeProfile.
+type RangeFunc func(Unit, Unit) bool
+
+func (rf RangeFunc) InRange(attacker Unit, target Unit) bool {
+	return rf(attacker, target)
+}
+
+// DistanceRange reaches units between Min and Max tiles away, counting
+// diagonal steps as one tile.
+type DistanceRange struct {
+	Min, Max int
+}
+
+func (dr DistanceRange) InRange(attacker Unit, target Unit) bool {
+	absDiff := attacker.GetPosition().Diff(target.GetPosition()).Abs()
+	distance := max(absDiff.X, absDiff.Y)
+	return distance >= dr.Min && distance <= dr.Max
+}
+
+var (
+	// MeleeRange reaches the eight surrounding tiles. It is the profile of
+	// every unit that doesn't set its own.
+	MeleeRange RangeProfile = DistanceRange{Min: 1, Max: 1}
+	// RangedRange reaches anywhere on the board.
+	RangedRange RangeProfile = RangeFunc(func(attacker Unit, target Unit) bool {
+		return true
+	})
+	// BlastRange reaches any unit in the same row or column.
+	BlastRange RangeProfile = RangeFunc(func(attacker Unit, target Unit) bool {
+		isInline, _ := attacker.IsInline(target)
+		return isInline
+	})
+)
+
+// RegisterRangeKeyword extends the reach of every unit with the keyword,
+// registering the keyword if it is new.
+func RegisterRangeKeyword(keyword Keyword, profile RangeProfile) {
+	defineKeyword(keyword).Range = profile
+}
+
+// unitRange combines a unit's own profile, or melee range if it has none, with
+// the profiles of its range keywords; the unit can reach a target if any of
+// them can.
+type unitRange struct {
+	unit    Unit
+	profile RangeProfile
+}
+
+func (ur unitRange) InRange(attacker Unit, target Unit) bool {
+	profile := ur.profile
+	if profile == nil {
+		profile = MeleeRange
+	}
+
+	if profile.InRange(attacker, target) {
+		return true
+	}
+
+	for _, definition := range keywords {
+		if definition.Range != nil && ur.unit.HasAttribute(definition.Keyword) && definition.Range.InRange(attacker, target) {
+			return true
+		}
+	}
+
+	return false
+}
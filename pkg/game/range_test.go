@@ -0,0 +1,94 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_distanceRange(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+
+	// Attacks units exactly 2 tiles away; its own profile replaces melee
+	lancer := NewUnitFactory().SetName("lancer").SetUnitType("minion").SetHealth(3).SetAttack(2).SetRangeProfile(DistanceRange{Min: 2, Max: 2}).Create()
+	gs.MakeMove(&PlaceUnitAction{Owner: p1, Unit: lancer, Position: NewPosition(4, 2)})
+
+	near := NewMinion("near", 3, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: near, Position: NewPosition(5, 3)})
+	twoAway := NewMinion("twoAway", 3, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: twoAway, Position: NewPosition(6, 0)})
+	farAway := NewMinion("farAway", 3, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: farAway, Position: NewPosition(7, 2)})
+
+	assert.Equal(t, map[Unit]struct{}{twoAway: {}}, p1.Board.GetValidTargets(lancer))
+
+	// The melee target 2 tiles away cannot strike back
+	endTurns(gs, p1, p2)
+	gs.MakeMove(&AttackAction{Attacker: lancer, Defender: near})
+	assert.Equal(t, 3, near.GetHp())
+	gs.MakeMove(&AttackAction{Attacker: lancer, Defender: twoAway})
+	assert.Equal(t, 1, twoAway.GetHp())
+	assert.Equal(t, 3, lancer.GetHp())
+
+	// Nor can the lancer strike back at an adjacent attacker
+	endTurns(gs, p1)
+	gs.MakeMove(&AttackAction{Attacker: near, Defender: lancer})
+	assert.Equal(t, 2, lancer.GetHp())
+	assert.Equal(t, 3, near.GetHp())
+
+	// A new keyword only needs registering
+	RegisterRangeKeyword("longshot", DistanceRange{Min: 3, Max: 3})
+	defer UnregisterKeyword("longshot")
+	farAway.AddAttribute("longshot", 0)
+	assert.Equal(t, map[Unit]struct{}{lancer: {}}, p1.Board.GetValidTargets(farAway))
+	gs.MakeMove(&AttackAction{Attacker: farAway, Defender: lancer})
+	assert.Equal(t, 1, lancer.GetHp())
+	assert.Equal(t, 3, farAway.GetHp())
+}
+
+func Test_provokeTargets(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+	p1general := p1.GetGeneral()
+	p1general.AddAttribute("ranged", 0)
+
+	all := p1.Board.GetValidTargets(p1general)
+	assert.Equal(t, map[Unit]struct{}{p2.GetGeneral(): {}}, all)
+
+	guard := NewMinion("guard", 3, 1)
+	guard.AddAttribute("provoke", 0)
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: guard, Position: NewPosition(1, 1)})
+	other := NewMinion("other", 3, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: other, Position: NewPosition(1, 2)})
+
+	// Ranged units next to a provoker must attack it too
+	assert.Equal(t, []Unit{guard}, p1.Board.GetProvokers(p1general))
+	assert.Equal(t, map[Unit]struct{}{guard: {}}, p1.Board.GetValidTargets(p1general))
+
+	// Attacks on anything else are refused, and the general is held in place
+	start := p1general.GetPosition()
+	gs.MakeMove(&AttackAction{Attacker: p1general, Defender: other})
+	assert.Equal(t, 3, other.GetHp())
+	assert.Empty(t, p1.Board.GetValidMoves(p1general))
+	gs.MakeMove(&MoveAction{Unit: p1general, Position: NewPosition(0, 4)})
+	assert.Equal(t, start, p1general.GetPosition())
+
+	// Friendly units are never targets
+	friend := NewMinion("friend", 3, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p1, Unit: friend, Position: NewPosition(0, 1)})
+	gs.MakeMove(&AttackAction{Attacker: p1general, Defender: friend})
+	assert.Equal(t, 3, friend.GetHp())
+
+	guard.Dispel()
+	assert.Equal(t, 3, len(p1.Board.GetValidTargets(p1general)))
+	gs.MakeMove(&MoveAction{Unit: p1general, Position: NewPosition(0, 4)})
+	assert.Equal(t, NewPosition(0, 4), p1general.GetPosition())
+}
+
+func Test_rangeKeywordCleanup(t *testing.T) {
+	RegisterRangeKeyword("reach", DistanceRange{Min: 2, Max: 2})
+	UnregisterKeyword("reach")
+
+	_, ok := LookupKeyword("reach")
+	assert.False(t, ok)
+	assert.ErrorIs(t, NewUnitFactory().AddAttribute("reach", 0).Err(), ErrUnknownKeyword)
+}
@@ -1,15 +1,37 @@
 package game
 
-import "github.com/RGood/game_engine/pkg/gamestate"
+import (
+	"encoding/json"
+	"errors"
 
+	"github.com/RGood/game_engine/pkg/gamestate"
+)
+
+var (
+	ErrCannotCast       = errors.New("spells cannot be cast right now")
+	ErrInvalidTargets   = errors.New("targets do not match the spell")
+	ErrInsufficientMana = errors.New("not enough mana")
+	ErrNotYourTurn      = errors.New("it is not this player's turn")
+)
+
+// Spell is a card played from hand. Cast checks the spell can be played on
+// the targets before any mana is spent, then announces it with a
+// CastSpellAction; Resolve applies its effect once the SpellAction that
+// follows resolves.
 type Spell interface {
-	Cast(*Player, *gamestate.Gamestate, []Unit, []Position)
+	GetID() gamestate.EntityID
+	GetCost() int
+	GetTargeting() TargetSpec
+	Cast(*Player, *gamestate.Gamestate, []Unit, []Position) error
+	Resolve(*Player, *gamestate.Gamestate, []Unit, []Position)
 }
 
 type GenericSpell struct {
-	Name   string
-	Cost   int
-	Effect func(*Player, *gamestate.Gamestate, []Unit, []Position)
+	id        gamestate.EntityID
+	Name      string
+	Cost      int
+	Targeting TargetSpec
+	Effect    func(*Player, *gamestate.Gamestate, []Unit, []Position)
 }
 
 func NewGenericSpell(name string, cost int, effect func(*Player, *gamestate.Gamestate, []Unit, []Position)) *GenericSpell {
@@ -20,21 +42,97 @@ func NewGenericSpell(name string, cost int, effect func(*Player, *gamestate.Game
 	}
 }
 
-func (spell *GenericSpell) Cast(owner *Player, gs *gamestate.Gamestate, units []Unit, positions []Position) {
-	gs.MakeMove(&SpellAction{
-		Owner: owner,
-		Spell: spell,
-		Effect: func() {
-			spell.Effect(owner, gs, units, positions)
-		},
+// payForSpell checks a cast is legal and spends its effective cost, which it
+// returns.
+func payForSpell(owner *Player, gs *gamestate.Gamestate, spell Spell, units []Unit, positions []Position) (int, error) {
+	if err := owner.canPlay(gs); err != nil {
+		return 0, err
+	}
+
+	if err := spell.GetTargeting().Check(owner, units, positions); err != nil {
+		return 0, err
+	}
+
+	cost, ok := owner.payForCard(spell)
+	if !ok {
+		return 0, ErrInsufficientMana
+	}
+
+	return cost, nil
+}
+
+// castSpell pays for a spell and announces it.
+func castSpell(owner *Player, gs *gamestate.Gamestate, spell Spell, units []Unit, positions []Position) error {
+	cost, err := payForSpell(owner, gs, spell, units, positions)
+	if err != nil {
+		return err
+	}
+	owner.enterZone(spell)
+	owner.leaveHand(spell)
+
+	gs.MakeMove(&CastSpellAction{
+		Owner:     owner,
+		Spell:     spell,
+		Cost:      cost,
+		Targeting: spell.GetTargeting(),
+		Units:     units,
+		Tiles:     positions,
+	})
+
+	return nil
+}
+
+// spellJSON is how a spell is serialized.
+type spellJSON struct {
+	ID        gamestate.EntityID
+	Name      string
+	Cost      int
+	Damage    int `json:",omitempty"`
+	Targeting TargetSpec
+}
+
+func (spell *GenericSpell) GetID() gamestate.EntityID {
+	return spell.id
+}
+
+func (spell *GenericSpell) GetCost() int {
+	return spell.Cost
+}
+
+func (spell *GenericSpell) GetTargeting() TargetSpec {
+	return spell.Targeting
+}
+
+func (spell *GenericSpell) assignID(ub *UnitBoard) {
+	if spell.id == 0 {
+		spell.id = ub.NewID(spell)
+	}
+}
+
+func (spell *GenericSpell) MarshalJSON() ([]byte, error) {
+	return json.Marshal(spellJSON{
+		ID:        spell.id,
+		Name:      spell.Name,
+		Cost:      spell.Cost,
+		Targeting: spell.Targeting,
 	})
 }
 
+func (spell *GenericSpell) Cast(owner *Player, gs *gamestate.Gamestate, units []Unit, positions []Position) error {
+	return castSpell(owner, gs, spell, units, positions)
+}
+
+func (spell *GenericSpell) Resolve(owner *Player, gs *gamestate.Gamestate, units []Unit, positions []Position) {
+	spell.Effect(owner, gs, units, positions)
+}
+
 type DamageSpell struct {
-	Name   string
-	Cost   int
-	Damage int
-	Effect func(*Player, *gamestate.Gamestate, int, []Unit, []Position)
+	id        gamestate.EntityID
+	Name      string
+	Cost      int
+	Damage    int
+	Targeting TargetSpec
+	Effect    func(*Player, *gamestate.Gamestate, int, []Unit, []Position)
 }
 
 func NewDamageSpell(name string, cost int, damage int, effect func(*Player, *gamestate.Gamestate, int, []Unit, []Position)) *DamageSpell {
@@ -46,13 +144,38 @@ func NewDamageSpell(name string, cost int, damage int, effect func(*Player, *gam
 	}
 }
 
-func (ds *DamageSpell) Cast(owner *Player, gs *gamestate.Gamestate, units []Unit, positions []Position) {
-	gs.MakeMove(&SpellAction{
-		Owner: owner,
-		Spell: ds,
-		Units: units,
-		Effect: func() {
-			ds.Effect(owner, gs, ds.Damage, units, positions)
-		},
+func (ds *DamageSpell) GetID() gamestate.EntityID {
+	return ds.id
+}
+
+func (ds *DamageSpell) GetCost() int {
+	return ds.Cost
+}
+
+func (ds *DamageSpell) GetTargeting() TargetSpec {
+	return ds.Targeting
+}
+
+func (ds *DamageSpell) assignID(ub *UnitBoard) {
+	if ds.id == 0 {
+		ds.id = ub.NewID(ds)
+	}
+}
+
+func (ds *DamageSpell) MarshalJSON() ([]byte, error) {
+	return json.Marshal(spellJSON{
+		ID:        ds.id,
+		Name:      ds.Name,
+		Cost:      ds.Cost,
+		Damage:    ds.Damage,
+		Targeting: ds.Targeting,
 	})
 }
+
+func (ds *DamageSpell) Cast(owner *Player, gs *gamestate.Gamestate, units []Unit, positions []Position) error {
+	return castSpell(owner, gs, ds, units, positions)
+}
+
+func (ds *DamageSpell) Resolve(owner *Player, gs *gamestate.Gamestate, units []Unit, positions []Position) {
+	ds.Effect(owner, gs, ds.Damage, units, positions)
+}
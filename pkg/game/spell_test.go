@@ -9,6 +9,7 @@ import (
 
 func Test_boardSpell(t *testing.T) {
 	p1, _, gs := setupGamestate()
+	p1.Mana = 9
 	units := p1.GetUnits()
 	p1general := units[0]
 
@@ -108,6 +109,9 @@ func Test_tileSpell(t *testing.T) {
 
 	})
 
+	gs.MakeMove(&EndTurnAction{Owner: p1})
+	p2.Mana = 4
+
 	chromaticCold.Cast(p2, gs, nil, []Position{NewPosition(1, 2)})
 	chromaticCold.Cast(p2, gs, nil, []Position{NewPosition(7, 2)})
 
@@ -117,6 +121,7 @@ func Test_tileSpell(t *testing.T) {
 
 func Test_multiTileSpell(t *testing.T) {
 	p1, _, gs := setupGamestate()
+	p1.Mana = 9
 
 	bonechillBarrier := NewGenericSpell("Bonechill Barrier", 2, func(owner *Player, gs *gamestate.Gamestate, _ []Unit, tiles []Position) {
 		if len(tiles) <= 3 {
@@ -176,7 +181,8 @@ func Test_multiTileSpell(t *testing.T) {
 }
 
 func Test_dispelMovedWall(t *testing.T) {
-	p1, _, gs := setupGamestate()
+	p1, p2, gs := setupGamestate()
+	p1.Mana = 9
 
 	bonechillBarrier := NewGenericSpell("Bonechill Barrier", 2, func(owner *Player, gs *gamestate.Gamestate, _ []Unit, tiles []Position) {
 		if len(tiles) <= 3 {
@@ -229,6 +235,10 @@ func Test_dispelMovedWall(t *testing.T) {
 
 	wall := walls[0]
 
+	// Walls are summoned exhausted, so wait a round before moving this one
+	gs.MakeMove(&EndTurnAction{Owner: p1})
+	gs.MakeMove(&EndTurnAction{Owner: p2})
+
 	gs.MakeMove(&MoveAction{
 		wall,
 		NewPosition(4, 2),
@@ -243,6 +253,8 @@ func Test_dispelMovedWall(t *testing.T) {
 
 func Test_endOfTurnListener(t *testing.T) {
 	p1, p2, gs := setupGamestate()
+	p1.Mana = 9
+	p2.Mana = 9
 	p1units := p1.GetUnits()
 	p1general := p1units[0]
 	p2units := p2.GetUnits()
@@ -343,7 +355,7 @@ func Test_saberspineSeal(t *testing.T) {
 
 	assert.Equal(t, 5, p1general.GetAttack())
 
-	gs.MakeMove(&EndTurnAction{})
+	gs.MakeMove(&EndTurnAction{Owner: p1})
 
 	assert.Equal(t, 2, p1general.GetAttack())
 
@@ -363,8 +375,258 @@ func Test_saberspineSeal(t *testing.T) {
 	assert.Equal(t, 15, p1general.GetHp())
 	assert.Equal(t, 18, p2general.GetHp())
 
-	gs.MakeMove(&EndTurnAction{})
+	gs.MakeMove(&EndTurnAction{Owner: p2})
 
 	assert.Equal(t, 2, p1general.GetAttack())
 	assert.Equal(t, 2, p2general.GetAttack())
 }
+
+func Test_chooseOneSpell(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+	p1general := p1.GetGeneral()
+	p2general := p2.GetGeneral()
+
+	gs.MakeMove(&DamageAction{Unit: p1general, Damage: 5})
+
+	// Choose one: deal 3 damage to the enemy general, or restore 3 health to yours
+	choice := NewGenericSpell("Choice", 1, func(owner *Player, game *gamestate.Gamestate, _ []Unit, _ []Position) {
+		game.RequestChoice(&gamestate.Prompt{
+			Player:  owner,
+			Options: []string{"Deal 3 damage", "Restore 3 health"},
+			Default: 0,
+			OnAnswer: func(choice int, game *gamestate.Gamestate) {
+				if choice == 0 {
+					game.QueueAction(&DamageAction{Unit: p2general, Damage: 3})
+				} else {
+					game.QueueAction(&HealAction{Unit: p1general, Heal: 3})
+				}
+			},
+		})
+	})
+
+	choice.Cast(p1, gs, nil, nil)
+	assert.NotNil(t, gs.PendingPrompt())
+	assert.Equal(t, 20, p1general.GetHp())
+	assert.Equal(t, 25, p2general.GetHp())
+
+	assert.Nil(t, gs.AnswerPrompt(p1, 1))
+	assert.Equal(t, 23, p1general.GetHp())
+	assert.Equal(t, 25, p2general.GetHp())
+
+	choice.Cast(p1, gs, nil, nil)
+	assert.Nil(t, gs.TimeoutPrompt())
+	assert.Equal(t, 22, p2general.GetHp())
+}
+
+func Test_counterspell(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+	p1.Mana = 9
+	p2.Mana = 9
+	p2general := p2.GetGeneral()
+
+	phoenixFire := NewDamageSpell("Phoenix Fire", 2, 3, func(owner *Player, game *gamestate.Gamestate, damage int, targets []Unit, _ []Position) {
+		if len(targets) == 1 {
+			game.QueueAction(&DamageAction{Unit: targets[0], Damage: damage})
+		}
+	})
+
+	// Counter the next enemy spell this turn, refunding it only if asked
+	counter := func(refund bool) gamestate.Interceptor {
+		countered := false
+		return NewUntilEndOfTurnInterceptor(
+			func(action gamestate.Action, _ *gamestate.Gamestate) bool {
+				sa, ok := action.(*SpellAction)
+				return ok && !countered && sa.Owner != p2
+			},
+			func(_ gamestate.Interceptor, action gamestate.Action, _ *gamestate.Gamestate) gamestate.Action {
+				countered = true
+				if refund {
+					return gamestate.CancelWithRefund(action, nil)
+				}
+				return gamestate.Cancel(action, nil)
+			},
+		)
+	}
+
+	var cancelledBy gamestate.Interceptor
+	NewExecuteOnceListener(func(action gamestate.Action, _ *gamestate.Gamestate) bool {
+		_, ok := action.(*gamestate.CancelledAction)
+		return ok
+	}, func(_ gamestate.Listener, action gamestate.Action, _ *gamestate.Gamestate) {
+		cancelledBy = action.(*gamestate.CancelledAction).By
+	}).Subscribe(gs)
+
+	noRefund := counter(false)
+	noRefund.Subscribe(gs)
+	phoenixFire.Cast(p1, gs, []Unit{p2general}, nil)
+
+	assert.Equal(t, 25, p2general.GetHp())
+	assert.Equal(t, 7, p1.Mana)
+	assert.Equal(t, noRefund, cancelledBy)
+
+	// Only the next spell is countered
+	phoenixFire.Cast(p1, gs, []Unit{p2general}, nil)
+	assert.Equal(t, 22, p2general.GetHp())
+	assert.Equal(t, 5, p1.Mana)
+
+	counter(true).Subscribe(gs)
+	phoenixFire.Cast(p1, gs, []Unit{p2general}, nil)
+
+	assert.Equal(t, 22, p2general.GetHp())
+	assert.Equal(t, 5, p1.Mana)
+}
+
+func Test_insufficientMana(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+	p2general := p2.GetGeneral()
+	p1.Mana = 1
+
+	phoenixFire := NewDamageSpell("Phoenix Fire", 2, 3, func(owner *Player, game *gamestate.Gamestate, damage int, targets []Unit, _ []Position) {
+		game.QueueAction(&DamageAction{Unit: targets[0], Damage: damage})
+	})
+
+	phoenixFire.Cast(p1, gs, []Unit{p2general}, nil)
+	assert.Equal(t, 25, p2general.GetHp())
+	assert.Equal(t, 1, p1.Mana)
+}
+
+func Test_spellTargeting(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+	p1.Mana = 9
+	p2.Mana = 9
+	p2general := p2.GetGeneral()
+
+	goblin := NewMinion("goblin", 2, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: goblin, Position: NewPosition(7, 2)})
+
+	phoenixFire := NewDamageSpell("Phoenix Fire", 2, 3, func(owner *Player, game *gamestate.Gamestate, damage int, targets []Unit, _ []Position) {
+		game.QueueAction(&DamageAction{Unit: targets[0], Damage: damage})
+	})
+	phoenixFire.Targeting = TargetSpec{Kind: TargetEnemyMinion}
+
+	assert.Equal(t, []Unit{goblin}, phoenixFire.GetTargeting().ValidUnits(p1))
+
+	// Illegal casts cost nothing
+	assert.ErrorIs(t, phoenixFire.Cast(p1, gs, []Unit{p2general}, nil), ErrInvalidTargets)
+	assert.ErrorIs(t, phoenixFire.Cast(p1, gs, nil, nil), ErrInvalidTargets)
+	assert.Equal(t, 9, p1.Mana)
+	assert.Equal(t, 25, p2general.GetHp())
+
+	assert.NoError(t, phoenixFire.Cast(p1, gs, []Unit{goblin}, nil))
+	assert.Equal(t, 7, p1.Mana)
+	assert.False(t, goblin.IsAlive())
+
+	// A spell whose target died before it resolved fizzles
+	resolved := false
+	gs.MakeMove(&SpellAction{
+		Owner:     p1,
+		Spell:     phoenixFire,
+		Targeting: phoenixFire.Targeting,
+		Units:     []Unit{goblin},
+		Effect: func() {
+			resolved = true
+		},
+	})
+	assert.False(t, resolved)
+}
+
+func Test_tileTargeting(t *testing.T) {
+	p1, p2, _ := setupGamestate()
+
+	ownSide := TargetSpec{Kind: TargetOwnSideTile}
+	assert.Len(t, ownSide.ValidTiles(p1), 4*5)
+	assert.True(t, ownSide.IsValidTile(p1, NewPosition(3, 0)))
+	assert.False(t, ownSide.IsValidTile(p1, NewPosition(4, 0)))
+	assert.True(t, ownSide.IsValidTile(p2, NewPosition(5, 0)))
+
+	area := TargetSpec{Kind: TargetArea}
+	assert.Len(t, area.ValidTiles(p1), 8*4)
+	assert.NoError(t, area.Check(p1, nil, []Position{NewPosition(7, 3)}))
+	assert.ErrorIs(t, area.Check(p1, nil, []Position{NewPosition(8, 3)}), ErrInvalidTargets)
+
+	tiles := TargetSpec{Kind: TargetTiles, Count: 2}
+	assert.NoError(t, tiles.Check(p1, nil, []Position{NewPosition(0, 0), NewPosition(1, 0)}))
+	assert.ErrorIs(t, tiles.Check(p1, nil, []Position{NewPosition(0, 0), NewPosition(0, 0)}), ErrInvalidTargets)
+	assert.ErrorIs(t, tiles.Check(p1, nil, []Position{NewPosition(0, 0)}), ErrInvalidTargets)
+
+	general := TargetSpec{Kind: TargetFriendlyGeneral}
+	assert.Equal(t, []Unit{p1.GetGeneral()}, general.ValidUnits(p1))
+}
+
+func Test_spellReactions(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+	p1.Mana = 9
+	p2.Mana = 9
+	p1general := p1.GetGeneral()
+	p2general := p2.GetGeneral()
+
+	phoenixFire := NewDamageSpell("Phoenix Fire", 2, 3, func(owner *Player, game *gamestate.Gamestate, damage int, targets []Unit, _ []Position) {
+		game.QueueAction(&DamageAction{Unit: targets[0], Damage: damage})
+	})
+	phoenixFire.Targeting = TargetSpec{Kind: TargetAnyUnit}
+
+	// Listeners see the announcement before the spell resolves
+	events := []string{}
+	recorder := NewUntilEndOfTurnListener(func(action gamestate.Action, _ *gamestate.Gamestate) bool {
+		return true
+	}, func(_ gamestate.Listener, action gamestate.Action, _ *gamestate.Gamestate) {
+		switch action.(type) {
+		case *CastSpellAction:
+			events = append(events, "cast")
+		case *SpellAction:
+			events = append(events, "resolved")
+		}
+	})
+	recorder.Subscribe(gs)
+
+	phoenixFire.Cast(p1, gs, []Unit{p2general}, nil)
+	assert.Equal(t, []string{"cast", "resolved"}, events)
+	assert.Equal(t, 22, p2general.GetHp())
+
+	// Redirect the next spell to its caster's general
+	redirected := false
+	NewUntilEndOfTurnInterceptor(func(action gamestate.Action, _ *gamestate.Gamestate) bool {
+		_, ok := action.(*CastSpellAction)
+		return ok && !redirected
+	}, func(_ gamestate.Interceptor, action gamestate.Action, _ *gamestate.Gamestate) gamestate.Action {
+		redirected = true
+		cast := action.(*CastSpellAction)
+		cast.Units = []Unit{cast.Owner.GetGeneral()}
+		return cast
+	}).Subscribe(gs)
+
+	phoenixFire.Cast(p1, gs, []Unit{p2general}, nil)
+	assert.Equal(t, 22, p2general.GetHp())
+	assert.Equal(t, 22, p1general.GetHp())
+
+	// Copy the next spell for the opponent, aimed back at the caster
+	NewExecuteOnceListener(func(action gamestate.Action, _ *gamestate.Gamestate) bool {
+		_, ok := action.(*CastSpellAction)
+		return ok
+	}, func(_ gamestate.Listener, action gamestate.Action, game *gamestate.Gamestate) {
+		cast := action.(*CastSpellAction)
+		copied := cast.Copy(p2)
+		copied.Units = []Unit{cast.Owner.GetGeneral()}
+		game.QueueAction(copied)
+	}).Subscribe(gs)
+
+	phoenixFire.Cast(p1, gs, []Unit{p2general}, nil)
+	assert.Equal(t, 19, p2general.GetHp())
+	assert.Equal(t, 19, p1general.GetHp())
+	assert.Equal(t, 3, p1.Mana)
+	assert.Equal(t, 9, p2.Mana)
+
+	// Countering the announcement stops the spell before it resolves
+	NewUntilEndOfTurnInterceptor(func(action gamestate.Action, _ *gamestate.Gamestate) bool {
+		_, ok := action.(*CastSpellAction)
+		return ok
+	}, func(_ gamestate.Interceptor, action gamestate.Action, _ *gamestate.Gamestate) gamestate.Action {
+		return gamestate.CancelWithRefund(action, nil)
+	}).Subscribe(gs)
+
+	events = []string{}
+	phoenixFire.Cast(p1, gs, []Unit{p2general}, nil)
+	assert.Equal(t, []string{}, events)
+	assert.Equal(t, 19, p2general.GetHp())
+	assert.Equal(t, 3, p1.Mana)
+}
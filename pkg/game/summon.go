@@ -0,0 +1,140 @@
+package game
+
+import (
+	"errors"
+
+	"github.com/RGood/game_engine/pkg/gamestate"
+)
+
+var (
+	ErrNoSpace        = errors.New("no space to summon on")
+	ErrNoMatchingCard = errors.New("no card matches")
+)
+
+// Play pays for a minion card from its owner's mana, with any cost modifiers
+// for it, and plays a fresh unit from it onto the tile.
+func (uf *UnitFactory) Play(owner *Player, gs *gamestate.Gamestate, position Position) (Unit, error) {
+	if err := owner.canPlay(gs); err != nil {
+		return nil, err
+	}
+
+	if err := uf.Err(); err != nil {
+		return nil, err
+	}
+
+	if !canSummonOn(owner.Board, position) {
+		return nil, ErrNoSpace
+	}
+
+	if _, ok := owner.payForCard(uf); !ok {
+		return nil, ErrInsufficientMana
+	}
+	owner.leaveHand(uf)
+
+	unit := uf.Create()
+	gs.MakeMove(&PlaceUnitAction{
+		Owner:    owner,
+		Unit:     unit,
+		Position: position,
+	})
+
+	return unit, nil
+}
+
+// SummonUnitAction puts a unit into play through an effect. Listeners can
+// tell it apart from a PlaceUnitAction, which plays a unit from hand. The
+// summon fizzles if the tile was filled before it resolved.
+type SummonUnitAction struct {
+	Owner    *Player
+	Unit     Unit
+	Position Position
+}
+
+func (sa *SummonUnitAction) Execute(gs *gamestate.Gamestate) *gamestate.Gamestate {
+	if !canSummonOn(sa.Owner.Board, sa.Position) {
+		return gs
+	}
+
+	putIntoPlay(gs, sa.Owner, sa.Unit, sa.Position)
+
+	return gs
+}
+
+func (sa *SummonUnitAction) AffectedUnits() []Unit {
+	return []Unit{sa.Unit}
+}
+
+func canSummonOn(board *UnitBoard, position Position) bool {
+	return position.IsOnBoard(board) && !board.IsOccupied(position)
+}
+
+// The summon helpers are meant for effects: they queue a SummonUnitAction in
+// the current chain and return the unit it will summon. They return ErrNoSpace
+// without creating anything if the tile is taken. Summons are paid for by the
+// card that makes them, so they cost no mana themselves.
+
+// SummonCard summons a fresh unit from the card registry.
+func SummonCard(gs *gamestate.Gamestate, owner *Player, id CardID, position Position) (Unit, error) {
+	if !canSummonOn(owner.Board, position) {
+		return nil, ErrNoSpace
+	}
+
+	unit, err := CreateCard(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return summon(gs, owner, unit, position), nil
+}
+
+// SummonCopy summons a copy of a unit. With buffs, the copy keeps the unit's
+// current stats, keywords and triggers; without them it is a fresh unit from
+// the unit's card, which must be registered.
+func SummonCopy(gs *gamestate.Gamestate, owner *Player, unit Unit, position Position, withBuffs bool) (Unit, error) {
+	if !withBuffs {
+		return SummonCard(gs, owner, unit.GetCardID(), position)
+	}
+
+	if !canSummonOn(owner.Board, position) {
+		return nil, ErrNoSpace
+	}
+
+	return summon(gs, owner, unit.Copy(), position), nil
+}
+
+// SummonRandom summons a random registered card matching the filter. Cards
+// are filtered by their definitions, so only the picked unit is created.
+// Picks use the game's seeded RNG.
+func SummonRandom(gs *gamestate.Gamestate, owner *Player, position Position, filter func(*UnitFactory) bool) (Unit, error) {
+	if !canSummonOn(owner.Board, position) {
+		return nil, ErrNoSpace
+	}
+
+	matching := []CardID{}
+	for _, card := range cards {
+		if filter(card.factory) {
+			matching = append(matching, card.id)
+		}
+	}
+
+	if len(matching) == 0 {
+		return nil, ErrNoMatchingCard
+	}
+
+	unit, err := CreateCard(matching[gs.Rand().Intn(len(matching))])
+	if err != nil {
+		return nil, err
+	}
+
+	return summon(gs, owner, unit, position), nil
+}
+
+func summon(gs *gamestate.Gamestate, owner *Player, unit Unit, position Position) Unit {
+	gs.QueueAction(&SummonUnitAction{
+		Owner:    owner,
+		Unit:     unit,
+		Position: position,
+	})
+
+	return unit
+}
@@ -0,0 +1,129 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/RGood/game_engine/pkg/gamestate"
+	"github.com/stretchr/testify/assert"
+)
+
+// isolateCards gives the test an empty card registry and puts the shared one
+// back when the test ends.
+func isolateCards(t *testing.T) {
+	saved := cards
+	cards = []registeredCard{}
+	t.Cleanup(func() {
+		cards = saved
+	})
+}
+
+func Test_cardRegistry(t *testing.T) {
+	isolateCards(t)
+	assert.ErrorIs(t, RegisterCard("typo", NewUnitFactory().AddAttribute("rnaged", 0)), ErrUnknownKeyword)
+
+	assert.NoError(t, RegisterCard("test-heartseeker", NewUnitFactory().SetName("Heartseeker").SetHealth(1).SetAttack(1).AddAttribute(Ranged, 0)))
+	first, err := CreateCard("test-heartseeker")
+	assert.NoError(t, err)
+	second, _ := CreateCard("test-heartseeker")
+
+	first.RemoveAttribute(Ranged)
+	assert.True(t, second.HasAttribute(Ranged))
+	assert.Equal(t, CardID("test-heartseeker"), second.GetCardID())
+
+	_, err = CreateCard("missing")
+	assert.ErrorIs(t, err, ErrUnknownCard)
+	assert.Equal(t, []CardID{"test-heartseeker"}, CardIDs())
+}
+
+func Test_summonHelpers(t *testing.T) {
+	isolateCards(t)
+	p1, _, gs := setupGamestate()
+	assert.NoError(t, RegisterCard("test-spriggin", NewUnitFactory().SetName("Spriggin").SetUnitType("minion").SetHealth(3).SetAttack(3)))
+	assert.NoError(t, RegisterCard("test-imp", NewUnitFactory().SetName("Imp").SetUnitType("minion").SetHealth(1).SetAttack(1)))
+
+	events := []string{}
+	NewUntilEndOfTurnListener(func(action gamestate.Action, _ *gamestate.Gamestate) bool {
+		return true
+	}, func(_ gamestate.Listener, action gamestate.Action, _ *gamestate.Gamestate) {
+		switch action.(type) {
+		case *PlaceUnitAction:
+			events = append(events, "played")
+		case *SummonUnitAction:
+			events = append(events, "summoned")
+		}
+	}).Subscribe(gs)
+
+	var summoned []Unit
+	var errs []error
+	effect := func(summonUnits func(*gamestate.Gamestate) (Unit, error)) {
+		NewGenericSpell("Summon", 0, func(_ *Player, gs *gamestate.Gamestate, _ []Unit, _ []Position) {
+			unit, err := summonUnits(gs)
+			summoned = append(summoned, unit)
+			errs = append(errs, err)
+		}).Cast(p1, gs, nil, nil)
+	}
+
+	effect(func(gs *gamestate.Gamestate) (Unit, error) {
+		return SummonCard(gs, p1, "test-spriggin", NewPosition(1, 1))
+	})
+	spriggin := summoned[0]
+	assert.Equal(t, spriggin, p1.Board.Positions[NewPosition(1, 1)])
+	assert.True(t, spriggin.IsExhausted())
+
+	// Copies keep buffs only when asked to
+	gs.MakeMove(&EffectAction{Unit: spriggin, Effect: func(u Unit) { u.BuffAttack(2) }})
+	effect(func(gs *gamestate.Gamestate) (Unit, error) {
+		return SummonCopy(gs, p1, spriggin, NewPosition(2, 1), true)
+	})
+	effect(func(gs *gamestate.Gamestate) (Unit, error) {
+		return SummonCopy(gs, p1, spriggin, NewPosition(3, 1), false)
+	})
+	assert.Equal(t, 5, summoned[1].GetAttack())
+	assert.Equal(t, 3, summoned[2].GetAttack())
+	assert.NotEqual(t, spriggin.GetID(), summoned[1].GetID())
+
+	// No space is an error, not a crash
+	effect(func(gs *gamestate.Gamestate) (Unit, error) {
+		return SummonCard(gs, p1, "test-spriggin", NewPosition(1, 1))
+	})
+	assert.Nil(t, summoned[3])
+	assert.ErrorIs(t, errs[3], ErrNoSpace)
+
+	effect(func(gs *gamestate.Gamestate) (Unit, error) {
+		return SummonRandom(gs, p1, NewPosition(4, 1), func(card *UnitFactory) bool {
+			return card.GetAttack() == 3
+		})
+	})
+	assert.Equal(t, "Spriggin", summoned[4].GetName())
+	assert.True(t, summoned[4].IsAlive())
+
+	effect(func(gs *gamestate.Gamestate) (Unit, error) {
+		return SummonRandom(gs, p1, NewPosition(5, 1), func(card *UnitFactory) bool {
+			return false
+		})
+	})
+	assert.ErrorIs(t, errs[5], ErrNoMatchingCard)
+
+	gs.MakeMove(&PlaceUnitAction{Owner: p1, Unit: NewMinion("hand", 1, 1), Position: NewPosition(6, 1)})
+	assert.Equal(t, []string{"summoned", "summoned", "summoned", "summoned", "played"}, events)
+}
+
+func Test_copyTriggersFollowTheCopy(t *testing.T) {
+	p1, _, gs := setupGamestate()
+
+	wall := NewWall("Bonechill Barrier", p1, 2, 0)
+	gs.MakeMove(&PlaceUnitAction{Owner: p1, Unit: wall, Position: NewPosition(3, 1)})
+
+	var copied Unit
+	NewGenericSpell("Mirror", 0, func(_ *Player, gs *gamestate.Gamestate, _ []Unit, _ []Position) {
+		copied, _ = SummonCopy(gs, p1, wall, NewPosition(3, 3), true)
+	}).Cast(p1, gs, nil, nil)
+
+	// Dispelling the copy removes the copy, not the wall it was copied from
+	gs.MakeMove(&DispelAction{Unit: copied})
+	assert.False(t, copied.IsAlive())
+	assert.True(t, wall.IsAlive())
+
+	gs.MakeMove(&DispelAction{Unit: wall})
+	assert.False(t, wall.IsAlive())
+}
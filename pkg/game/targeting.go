@@ -0,0 +1,147 @@
+package game
+
+// TargetKind is what a spell must be cast on.
+type TargetKind int
+
+const (
+	// TargetFree leaves target checks to the spell's effect.
+	TargetFree TargetKind = iota
+	// TargetNone takes no targets.
+	TargetNone
+	// TargetAnyUnit takes units of either side, generals included.
+	TargetAnyUnit
+	// TargetEnemyMinion takes enemy units that aren't generals.
+	TargetEnemyMinion
+	// TargetFriendlyGeneral takes the caster's own general.
+	TargetFriendlyGeneral
+	// TargetTiles takes any tiles on the board.
+	TargetTiles
+	// TargetArea takes the top left tile of a 2x2 area on the board.
+	TargetArea
+	// TargetOwnSideTile takes tiles on the caster's half of the board.
+	TargetOwnSideTile
+)
+
+// TargetSpec declares a spell's targets. Count is how many units or tiles the
+// spell takes and defaults to one; an area spell always takes one tile.
+type TargetSpec struct {
+	Kind  TargetKind
+	Count int
+}
+
+func (ts TargetSpec) count() int {
+	if ts.Kind == TargetNone {
+		return 0
+	}
+
+	if ts.Count < 1 || ts.Kind == TargetArea {
+		return 1
+	}
+
+	return ts.Count
+}
+
+func (ts TargetSpec) targetsUnits() bool {
+	return ts.Kind == TargetAnyUnit || ts.Kind == TargetEnemyMinion || ts.Kind == TargetFriendlyGeneral
+}
+
+func (ts TargetSpec) targetsTiles() bool {
+	return ts.Kind == TargetTiles || ts.Kind == TargetArea || ts.Kind == TargetOwnSideTile
+}
+
+// IsValidUnit reports whether the caster may choose the unit as a target.
+func (ts TargetSpec) IsValidUnit(owner *Player, unit Unit) bool {
+	if !ts.targetsUnits() || !unit.IsAlive() {
+		return false
+	}
+
+	if _, ok := owner.Board.Units[unit]; !ok {
+		return false
+	}
+
+	switch ts.Kind {
+	case TargetEnemyMinion:
+		return unit.GetOwner() != owner && unit.GetType() != "general"
+	case TargetFriendlyGeneral:
+		return unit.GetOwner() == owner && unit.GetType() == "general"
+	}
+
+	return true
+}
+
+// IsValidTile reports whether the caster may choose the tile as a target.
+func (ts TargetSpec) IsValidTile(owner *Player, tile Position) bool {
+	if !ts.targetsTiles() || !tile.IsOnBoard(owner.Board) {
+		return false
+	}
+
+	switch ts.Kind {
+	case TargetArea:
+		return len(owner.Board.Square2x2Tiles(tile)) == 4
+	case TargetOwnSideTile:
+		return owner.Board.isOnSide(tile, owner.FacesRight)
+	}
+
+	return true
+}
+
+// AffectedTiles expands chosen tiles into the tiles the spell covers: the
+// whole square for an area spell, and the tiles themselves otherwise.
+func (ts TargetSpec) AffectedTiles(owner *Player, tiles []Position) []Position {
+	if ts.Kind != TargetArea || len(tiles) != 1 {
+		return tiles
+	}
+
+	return owner.Board.Square2x2Tiles(tiles[0])
+}
+
+// ValidUnits lists the units the caster may target, in board order.
+func (ts TargetSpec) ValidUnits(owner *Player) []Unit {
+	return filterUnits(owner.Board.SortedUnits(), func(unit Unit) bool {
+		return ts.IsValidUnit(owner, unit)
+	})
+}
+
+// ValidTiles lists the tiles the caster may target, row by row.
+func (ts TargetSpec) ValidTiles(owner *Player) []Position {
+	return owner.Board.tilesWhere(func(tile Position) bool {
+		return ts.IsValidTile(owner, tile)
+	})
+}
+
+// Check returns ErrInvalidTargets unless the units and tiles are exactly what
+// the spec asks for, with no target chosen twice.
+func (ts TargetSpec) Check(owner *Player, units []Unit, tiles []Position) error {
+	if ts.Kind == TargetFree {
+		return nil
+	}
+
+	wantUnits, wantTiles := 0, 0
+	if ts.targetsUnits() {
+		wantUnits = ts.count()
+	} else if ts.targetsTiles() {
+		wantTiles = ts.count()
+	}
+
+	if len(units) != wantUnits || len(tiles) != wantTiles {
+		return ErrInvalidTargets
+	}
+
+	seenUnits := map[Unit]struct{}{}
+	for _, unit := range units {
+		if _, ok := seenUnits[unit]; ok || !ts.IsValidUnit(owner, unit) {
+			return ErrInvalidTargets
+		}
+		seenUnits[unit] = struct{}{}
+	}
+
+	seenTiles := map[Position]struct{}{}
+	for _, tile := range tiles {
+		if _, ok := seenTiles[tile]; ok || !ts.IsValidTile(owner, tile) {
+			return ErrInvalidTargets
+		}
+		seenTiles[tile] = struct{}{}
+	}
+
+	return nil
+}
@@ -0,0 +1,231 @@
+package game
+
+import (
+	"encoding/json"
+
+	"github.com/RGood/game_engine/pkg/gamestate"
+)
+
+// Tile is an entity lying on a board tile, such as a mana spring, creep or
+// terrain. Each board tile holds at most one. Owner is nil for neutral tiles.
+// Duration counts the turn ends it lasts (its owner's, or everyone's when
+// neutral); zero lasts until removed. Hooks may be nil; enter and leave hooks
+// run after the action that moved the unit, and turn start hooks run as each
+// player's turn starts.
+type Tile struct {
+	id          gamestate.EntityID
+	Name        string
+	Owner       *Player
+	Position    Position
+	Duration    int
+	OnEnter     func(tile *Tile, unit Unit, gs *gamestate.Gamestate)
+	OnLeave     func(tile *Tile, unit Unit, gs *gamestate.Gamestate)
+	OnTurnStart func(tile *Tile, active *Player, gs *gamestate.Gamestate)
+}
+
+func (tile *Tile) GetID() gamestate.EntityID {
+	return tile.id
+}
+
+// TileState is a tile entity's state as plain values, with its owner as an
+// ID. It is what snapshots keep and how tiles are serialized.
+type TileState struct {
+	ID       gamestate.EntityID
+	Name     string
+	Owner    gamestate.EntityID
+	Position Position
+	Duration int
+}
+
+func (tile *Tile) State() TileState {
+	return TileState{
+		ID:       tile.id,
+		Name:     tile.Name,
+		Owner:    tile.Owner.GetID(),
+		Position: tile.Position,
+		Duration: tile.Duration,
+	}
+}
+
+func (tile *Tile) MarshalJSON() ([]byte, error) {
+	return json.Marshal(tile.State())
+}
+
+// Occupant is the unit standing on the tile, if any.
+func (tile *Tile) Occupant(board *UnitBoard) Unit {
+	return board.Positions[tile.Position]
+}
+
+// NewManaSpring gives the owner of the first unit to step on it a mana, then
+// dries up.
+func NewManaSpring() *Tile {
+	return &Tile{
+		Name: "Mana Spring",
+		OnEnter: func(tile *Tile, unit Unit, gs *gamestate.Gamestate) {
+			gs.QueueAction(&GainManaAction{
+				Player: unit.GetOwner(),
+				Mana:   1,
+			})
+			gs.QueueAction(&RemoveTileAction{
+				Board: unit.GetBoard(),
+				Tile:  tile,
+			})
+		},
+	}
+}
+
+// NewShadowCreep damages an enemy standing on it as its owner's turn starts.
+func NewShadowCreep(owner *Player) *Tile {
+	return &Tile{
+		Name:  "Shadow Creep",
+		Owner: owner,
+		OnTurnStart: func(tile *Tile, active *Player, gs *gamestate.Gamestate) {
+			occupant := tile.Occupant(tile.Owner.Board)
+			if active == tile.Owner && occupant != nil && occupant.GetOwner() != tile.Owner {
+				gs.QueueAction(&DamageAction{
+					Unit:   occupant,
+					Damage: 1,
+				})
+			}
+		},
+	}
+}
+
+// GetTile returns the tile entity at a position, if any.
+func (ub *UnitBoard) GetTile(pos Position) *Tile {
+	return ub.tiles[pos]
+}
+
+// SortedTiles lists the board's tile entities by row then column.
+func (ub *UnitBoard) SortedTiles() []*Tile {
+	return ub.TilesIn(ub.AllTiles())
+}
+
+// TilesIn lists the tile entities on the given tiles, by row then column.
+func (ub *UnitBoard) TilesIn(positions []Position) []*Tile {
+	tiles := []*Tile{}
+	for _, pos := range ub.tilesWhere(func(pos Position) bool {
+		return containsTile(positions, pos)
+	}) {
+		if tile, ok := ub.tiles[pos]; ok {
+			tiles = append(tiles, tile)
+		}
+	}
+
+	return tiles
+}
+
+// watchTiles subscribes the listener that runs enter and leave hooks. It is
+// added when the first tile entity is placed.
+func (ub *UnitBoard) watchTiles(gs *gamestate.Gamestate) {
+	if ub.tileWatcher == nil {
+		ub.tileWatcher = NewPhaseListener(nil, ub.unitsMoved)
+	}
+
+	gs.Subscribe(ub.tileWatcher)
+}
+
+func (ub *UnitBoard) unitsMoved(action gamestate.Action, before []UnitView, after []UnitView, gs *gamestate.Gamestate) {
+	for index, view := range after {
+		from, to := before[index].Position, view.Position
+		if from == to {
+			continue
+		}
+
+		if tile, ok := ub.tiles[from]; ok && tile.OnLeave != nil {
+			tile.OnLeave(tile, view.Unit, gs)
+		}
+
+		if tile, ok := ub.tiles[to]; ok && tile.OnEnter != nil {
+			tile.OnEnter(tile, view.Unit, gs)
+		}
+	}
+}
+
+// startTurn counts down tile durations as a turn ends and runs the turn
+// start hooks for the next player.
+func (ub *UnitBoard) startTurn(ended *Player, active *Player, gs *gamestate.Gamestate) {
+	for _, tile := range ub.SortedTiles() {
+		if tile.Duration > 0 && (tile.Owner == nil || tile.Owner == ended) {
+			tile.Duration--
+			if tile.Duration == 0 {
+				gs.QueueAction(&RemoveTileAction{
+					Board: ub,
+					Tile:  tile,
+				})
+				continue
+			}
+		}
+
+		if tile.OnTurnStart != nil {
+			tile.OnTurnStart(tile, active, gs)
+		}
+	}
+}
+
+// PlaceTileAction puts a tile entity on the board, replacing any already on
+// that tile.
+type PlaceTileAction struct {
+	Board    *UnitBoard
+	Tile     *Tile
+	Position Position
+}
+
+func (pta *PlaceTileAction) Execute(gs *gamestate.Gamestate) *gamestate.Gamestate {
+	if !pta.Position.IsOnBoard(pta.Board) {
+		return gs
+	}
+
+	if pta.Tile.id == 0 {
+		pta.Tile.id = pta.Board.NewID(pta.Tile)
+	}
+
+	pta.Tile.Position = pta.Position
+	pta.Board.tiles[pta.Position] = pta.Tile
+	pta.Board.watchTiles(gs)
+
+	return gs
+}
+
+type RemoveTileAction struct {
+	Board *UnitBoard
+	Tile  *Tile
+}
+
+func (rta *RemoveTileAction) Execute(gs *gamestate.Gamestate) *gamestate.Gamestate {
+	if rta.Board.tiles[rta.Tile.Position] == rta.Tile {
+		delete(rta.Board.tiles, rta.Tile.Position)
+	}
+
+	return gs
+}
+
+type GainManaAction struct {
+	Player *Player
+	Mana   int
+}
+
+func (gma *GainManaAction) Execute(gs *gamestate.Gamestate) *gamestate.Gamestate {
+	gma.Player.GainMana(gma.Mana)
+
+	return gs
+}
+
+// restoreTile rebuilds a tile entity from its saved state. Its hooks come
+// from the tile that was given the ID, and the rebuilt tile takes its place
+// under that ID.
+func (ub *UnitBoard) restoreTile(state TileState) *Tile {
+	tile := &Tile{}
+	if original, ok := ub.entities[state.ID].(*Tile); ok {
+		*tile = *original
+	}
+
+	tile.id = state.ID
+	tile.Name = state.Name
+	tile.Owner, _ = ub.entities[state.Owner].(*Player)
+	tile.Position = state.Position
+	tile.Duration = state.Duration
+	ub.entities[state.ID] = tile
+
+	return tile
+}
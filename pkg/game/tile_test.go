@@ -0,0 +1,115 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/RGood/game_engine/pkg/gamestate"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_manaSpring(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+	p1.Mana = 9
+	p1general := p1.GetGeneral()
+	board := p1.Board
+
+	spring := NewManaSpring()
+	gs.MakeMove(&PlaceTileAction{Board: board, Tile: spring, Position: NewPosition(1, 2)})
+	assert.Equal(t, spring, board.GetTile(NewPosition(1, 2)))
+	assert.NotZero(t, spring.GetID())
+
+	entity, ok := gs.Lookup(spring.GetID())
+	assert.True(t, ok)
+	assert.Equal(t, spring, entity)
+
+	gs.MakeMove(&MoveAction{Unit: p1general, Position: NewPosition(1, 2)})
+	assert.Equal(t, 10, p1.Mana)
+	assert.Nil(t, board.GetTile(NewPosition(1, 2)))
+
+	endTurns(gs, p1, p2)
+	gs.MakeMove(&MoveAction{Unit: p1general, Position: NewPosition(0, 2)})
+	endTurns(gs, p1, p2)
+	gs.MakeMove(&MoveAction{Unit: p1general, Position: NewPosition(1, 2)})
+	assert.Equal(t, NewPosition(1, 2), p1general.GetPosition())
+	assert.Equal(t, 10, p1.Mana)
+}
+
+func Test_tilesDuringCombat(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+	p1general := p1.GetGeneral()
+	board := p1.Board
+
+	gs.MakeMove(&PlaceTileAction{Board: board, Tile: NewManaSpring(), Position: NewPosition(4, 4)})
+	gremlin := NewMinion("gremlin", 3, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: gremlin, Position: NewPosition(1, 2)})
+
+	// The counterattack adds a hit while combat damage resolves; the tile
+	// watcher still compares the units it saw beforehand
+	gs.MakeMove(&AttackAction{Attacker: p1general, Defender: gremlin})
+	assert.Equal(t, 1, gremlin.GetHp())
+	assert.Equal(t, 24, p1general.GetHp())
+}
+
+func Test_tileTriggers(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+	board := p1.Board
+
+	events := []string{}
+	hallowed := &Tile{
+		Name:  "Hallowed Ground",
+		Owner: p1,
+		OnEnter: func(tile *Tile, unit Unit, gs *gamestate.Gamestate) {
+			events = append(events, "enter "+unit.GetName())
+		},
+		OnLeave: func(tile *Tile, unit Unit, gs *gamestate.Gamestate) {
+			events = append(events, "leave "+unit.GetName())
+		},
+	}
+	creep := NewShadowCreep(p1)
+	creep.Duration = 2
+	gs.MakeMove(&PlaceTileAction{Board: board, Tile: hallowed, Position: NewPosition(4, 0)})
+	gs.MakeMove(&PlaceTileAction{Board: board, Tile: creep, Position: NewPosition(5, 1)})
+	assert.Equal(t, []*Tile{hallowed, creep}, board.SortedTiles())
+	assert.Equal(t, []*Tile{creep}, board.TilesIn(board.EnemySideTiles(p1)))
+
+	knight := NewMinion("knight", 3, 1)
+	gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: knight, Position: NewPosition(4, 0)})
+	gs.MakeMove(&TeleportAction{Unit: knight, Position: NewPosition(5, 1)})
+	assert.Equal(t, []string{"enter knight", "leave knight"}, events)
+
+	snapshot := gs.Snapshot()
+	p1.SpendMana(2)
+
+	// Creep only bites as its owner's turn starts, and wears off after two
+	// of its owner's turns
+	gs.MakeMove(&EndTurnAction{Owner: p1})
+	assert.Equal(t, 3, knight.GetHp())
+	gs.MakeMove(&EndTurnAction{Owner: p2})
+	assert.Equal(t, 2, knight.GetHp())
+	assert.Equal(t, 1, creep.Duration)
+	gs.MakeMove(&EndTurnAction{Owner: p1})
+	gs.MakeMove(&EndTurnAction{Owner: p2})
+	assert.Equal(t, 2, knight.GetHp())
+	assert.Nil(t, board.GetTile(NewPosition(5, 1)))
+
+	// Snapshots bring tiles back, rebuilt from their saved values
+	gs.Restore(snapshot)
+	restored := board.GetTile(NewPosition(5, 1))
+	assert.Equal(t, creep.GetID(), restored.GetID())
+	assert.Equal(t, p1, restored.Owner)
+	assert.Equal(t, 2, restored.Duration)
+	assert.Equal(t, StartingMana, p1.Mana)
+	// Snapshots don't cover units, so the knight keeps its damage
+	assert.Equal(t, 2, knight.GetHp())
+
+	entity, _ := gs.Lookup(creep.GetID())
+	assert.Equal(t, restored, entity)
+
+	// The snapshot is unaffected by the rebuilt tiles wearing off again
+	gs.MakeMove(&EndTurnAction{Owner: p1})
+	gs.MakeMove(&EndTurnAction{Owner: p2})
+	assert.Equal(t, 1, knight.GetHp())
+	assert.Equal(t, 1, restored.Duration)
+	gs.Restore(snapshot)
+	assert.Equal(t, 2, board.GetTile(NewPosition(5, 1)).Duration)
+}
@@ -1,8 +1,15 @@
 package game
 
-import "github.com/RGood/game_engine/pkg/gamestate"
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/RGood/game_engine/pkg/gamestate"
+)
 
 type Unit interface {
+	GetID() gamestate.EntityID
 	GetName() string
 	GetOwner() *Player
 	GetType() string
@@ -11,26 +18,40 @@ type Unit interface {
 	IsAlive() bool
 	GetAttack() int
 	GetWalkDistance() int
-	AddAttribute(string, int)
-	RemoveAttribute(string)
-	GetAttributeValue(string) int
-	HasAttribute(string) bool
+	AddAttribute(Keyword, int) error
+	RemoveAttribute(Keyword)
+	GetAttributeValue(Keyword) int
+	HasAttribute(Keyword) bool
 	IsEnemy(Unit) bool
 	InRange(Unit) bool
+	GetRangeProfile() RangeProfile
+	SetRangeProfile(RangeProfile)
 	IsNear(Unit) bool
 	IsInline(Unit) (bool, func(Unit) bool)
 	Damage(int)
 	GetPosition() Position
 	FacesRight() bool
+	SetFacing(bool)
 	GetValidMoves() map[Position]struct{}
 	Move(Position)
 	Place(*Player, Position)
+	SetOwner(*Player)
 	Remove()
+	IsExhausted() bool
+	Exhaust()
+	Refresh()
+	ActionsPerTurn() int
+	CanMove() bool
+	CanAttack() bool
+	SpendMove()
+	SpendAttack()
 	Dispel()
 	BuffAttack(int)
 	BuffHealth(int)
 	SetBoard(*UnitBoard)
 	GetBoard() *UnitBoard
+	GetCardID() CardID
+	Copy() Unit
 	AddActionTrigger(ActionTrigger) int
 	RemoveActionTrigger(int)
 	Subscribe(*gamestate.Gamestate)
@@ -40,6 +61,7 @@ type Unit interface {
 }
 
 type Minion struct {
+	id               gamestate.EntityID
 	name             string
 	unitType         string
 	subtypes         map[string]struct{}
@@ -51,27 +73,43 @@ type Minion struct {
 	baseAttack       int
 	attackDelta      int
 	damage           int
-	attributes       map[string]int
+	exhausted        bool
+	actionsUsed      int
+	moved            bool
+	attributes       map[Keyword]int
+	rangeProfile     RangeProfile
 	board            *UnitBoard
 	triggerCount     int
 	triggers         map[int]ActionTrigger
 	interceptorCount int
 	interceptors     map[int]InterceptTrigger
+	card             CardID
 }
 
+// Equal compares units by ID. Units that have never been on a board have no
+// ID yet and fall back to comparing name and position.
 func Equal(u1, u2 Unit) bool {
+	if u1.GetID() != 0 || u2.GetID() != 0 {
+		return u1.GetID() == u2.GetID()
+	}
+
 	return u1.GetName() == u2.GetName() &&
 		u1.GetBoard() == u2.GetBoard() &&
 		u1.GetPosition() == u2.GetPosition()
 }
 
+// ActionTrigger runs after each action. Trigger is handed the unit it belongs
+// to as self, so the same trigger works on every unit made from a card and on
+// copies; triggers should act through self rather than capture a unit.
 type ActionTrigger struct {
-	Trigger   func(gamestate.Action, *gamestate.Gamestate)
+	Trigger   func(self Unit, action gamestate.Action, gs *gamestate.Gamestate)
 	CanDispel bool
 }
 
+// InterceptTrigger can change or cancel each action before it executes. Like
+// ActionTrigger, Trigger is handed the unit it belongs to as self.
 type InterceptTrigger struct {
-	Trigger   func(gamestate.Action, *gamestate.Gamestate) gamestate.Action
+	Trigger   func(self Unit, action gamestate.Action, gs *gamestate.Gamestate) gamestate.Action
 	CanDispel bool
 }
 
@@ -81,17 +119,21 @@ type UnitFactory struct {
 	subtypes         map[string]struct{}
 	hp               int
 	attack           int
-	attributes       map[string]int
+	cost             int
+	attributes       map[Keyword]int
+	rangeProfile     RangeProfile
 	triggerCount     int
 	triggers         map[int]ActionTrigger
 	interceptorCount int
 	interceptors     map[int]InterceptTrigger
+	card             CardID
+	err              error
 }
 
 func NewUnitFactory() *UnitFactory {
 	return &UnitFactory{
 		subtypes:         map[string]struct{}{},
-		attributes:       map[string]int{},
+		attributes:       map[Keyword]int{},
 		triggerCount:     0,
 		triggers:         map[int]ActionTrigger{},
 		interceptorCount: 0,
@@ -124,8 +166,80 @@ func (uf *UnitFactory) SetAttack(attack int) *UnitFactory {
 	return uf
 }
 
-func (uf *UnitFactory) AddAttribute(name string, value int) *UnitFactory {
-	uf.attributes[name] = value
+func (uf *UnitFactory) SetCost(cost int) *UnitFactory {
+	uf.cost = cost
+	return uf
+}
+
+func (uf *UnitFactory) GetCost() int {
+	return uf.cost
+}
+
+// The card getters let effects such as SummonRandom pick cards by what they
+// would summon without creating the units.
+
+func (uf *UnitFactory) GetName() string {
+	return uf.name
+}
+
+func (uf *UnitFactory) GetType() string {
+	return uf.unitType
+}
+
+func (uf *UnitFactory) HasSubtype(subtype string) bool {
+	_, ok := uf.subtypes[subtype]
+	return ok
+}
+
+func (uf *UnitFactory) GetHp() int {
+	return uf.hp
+}
+
+func (uf *UnitFactory) GetAttack() int {
+	return uf.attack
+}
+
+func (uf *UnitFactory) HasAttribute(keyword Keyword) bool {
+	_, ok := uf.attributes[keyword]
+	return ok
+}
+
+// unitCardJSON is how a minion card is serialized while it is in hand.
+type unitCardJSON struct {
+	Card   CardID
+	Name   string
+	Cost   int
+	Hp     int
+	Attack int
+}
+
+func (uf *UnitFactory) MarshalJSON() ([]byte, error) {
+	return json.Marshal(unitCardJSON{
+		Card:   uf.card,
+		Name:   uf.name,
+		Cost:   uf.cost,
+		Hp:     uf.hp,
+		Attack: uf.attack,
+	})
+}
+
+// AddAttribute gives the unit a keyword. Unknown keywords, and values on
+// keywords that take none, are recorded in Err and make Create panic.
+func (uf *UnitFactory) AddAttribute(keyword Keyword, value int) *UnitFactory {
+	if err := addKeyword(uf.attributes, keyword, value); err != nil && uf.err == nil {
+		uf.err = fmt.Errorf("%s: %w", uf.name, err)
+	}
+
+	return uf
+}
+
+// Err reports the first problem with the unit definition.
+func (uf *UnitFactory) Err() error {
+	return uf.err
+}
+
+func (uf *UnitFactory) SetRangeProfile(profile RangeProfile) *UnitFactory {
+	uf.rangeProfile = profile
 	return uf
 }
 
@@ -143,20 +257,34 @@ func (uf *UnitFactory) AddIntercept(trigger InterceptTrigger) *UnitFactory {
 	return uf
 }
 
+// Create builds a unit from the definition. It panics if the definition is
+// invalid; check Err when definitions come from outside the code.
 func (uf *UnitFactory) Create() Unit {
-	return NewUnit(
-		uf.name,
-		uf.unitType,
-		uf.subtypes,
-		uf.attributes,
-		uf.hp,
-		uf.attack,
-		uf.triggers,
-		uf.interceptors,
-	)
-}
-
-func NewUnit(name string, unitType string, subtypes map[string]struct{}, attributes map[string]int, hp int, attack int, triggers map[int]ActionTrigger, interceptors map[int]InterceptTrigger) Unit {
+	if uf.err != nil {
+		panic(uf.err)
+	}
+
+	// Units must not share maps with the factory or with each other
+	unit := &Minion{
+		name:             uf.name,
+		unitType:         uf.unitType,
+		subtypes:         copySubtypes(uf.subtypes),
+		attributes:       copyAttributes(uf.attributes),
+		baseHp:           uf.hp,
+		baseAttack:       uf.attack,
+		walkDistance:     2,
+		rangeProfile:     uf.rangeProfile,
+		triggerCount:     uf.triggerCount,
+		triggers:         copyTriggers(uf.triggers),
+		interceptorCount: uf.interceptorCount,
+		interceptors:     copyInterceptors(uf.interceptors),
+		card:             uf.card,
+	}
+
+	return unit
+}
+
+func NewUnit(name string, unitType string, subtypes map[string]struct{}, attributes map[Keyword]int, hp int, attack int, triggers map[int]ActionTrigger, interceptors map[int]InterceptTrigger) Unit {
 	return &Minion{
 		name:             name,
 		unitType:         unitType,
@@ -183,7 +311,7 @@ func NewMinion(name string, hp int, attack int) *Minion {
 		baseAttack:   attack,
 		attackDelta:  0,
 		damage:       0,
-		attributes:   map[string]int{},
+		attributes:   map[Keyword]int{},
 		triggers:     map[int]ActionTrigger{},
 	}
 
@@ -202,7 +330,7 @@ func NewGeneral(name string, owner *Player) *Minion {
 		baseAttack:   2,
 		attackDelta:  0,
 		damage:       0,
-		attributes:   map[string]int{},
+		attributes:   map[Keyword]int{},
 		triggers:     map[int]ActionTrigger{},
 	}
 
@@ -222,17 +350,17 @@ func NewWall(name string, owner *Player, hp int, attack int) *Minion {
 		baseAttack:   attack,
 		attackDelta:  0,
 		damage:       0,
-		attributes:   map[string]int{},
+		attributes:   map[Keyword]int{},
 		triggers:     map[int]ActionTrigger{},
 	}
 
 	wall.AddActionTrigger(ActionTrigger{
-		Trigger: func(action gamestate.Action, gs *gamestate.Gamestate) {
+		Trigger: func(self Unit, action gamestate.Action, gs *gamestate.Gamestate) {
 			dispelAction, ok := action.(*DispelAction)
 			if ok {
-				if Equal(dispelAction.Unit, wall) {
+				if Equal(dispelAction.Unit, self) {
 					gs.QueueAction(&RemoveUnitAction{
-						Unit: wall,
+						Unit: self,
 					})
 				}
 			}
@@ -243,6 +371,66 @@ func NewWall(name string, owner *Player, hp int, attack int) *Minion {
 	return wall
 }
 
+func (m *Minion) GetID() gamestate.EntityID {
+	return m.id
+}
+
+// unitJSON is how a unit is serialized. Its owner is written as an ID, and
+// Position is nil while the unit is out of play.
+type unitJSON struct {
+	ID          gamestate.EntityID
+	Card        CardID
+	Name        string
+	Type        string
+	Subtypes    []string
+	Owner       gamestate.EntityID
+	Position    *Position
+	FacesRight  bool
+	Hp          int
+	Attack      int
+	Damage      int
+	Exhausted   bool
+	ActionsUsed int
+	Moved       bool
+	Keywords    map[Keyword]int
+}
+
+// MarshalJSON writes the unit's full state. Its ID can be resolved again with
+// Gamestate.Lookup, so actions referencing it survive serialization. The JSON
+// is for reading only: units carry triggers that cannot be serialized, so
+// they are never rebuilt from it.
+func (m *Minion) MarshalJSON() ([]byte, error) {
+	subtypes := []string{}
+	for subtype := range m.subtypes {
+		subtypes = append(subtypes, subtype)
+	}
+	sort.Strings(subtypes)
+
+	var position *Position
+	if m.IsAlive() {
+		pos := m.GetPosition()
+		position = &pos
+	}
+
+	return json.Marshal(unitJSON{
+		ID:          m.id,
+		Card:        m.card,
+		Name:        m.name,
+		Type:        m.unitType,
+		Subtypes:    subtypes,
+		Owner:       m.owner.GetID(),
+		Position:    position,
+		FacesRight:  m.faceRight,
+		Hp:          m.GetHp(),
+		Attack:      m.GetAttack(),
+		Damage:      m.damage,
+		Exhausted:   m.exhausted,
+		ActionsUsed: m.actionsUsed,
+		Moved:       m.moved,
+		Keywords:    copyAttributes(m.attributes),
+	})
+}
+
 func (m *Minion) GetType() string {
 	return m.unitType
 }
@@ -272,7 +460,7 @@ func (m *Minion) GetWalkDistance() int {
 	return m.walkDistance
 }
 
-func (m *Minion) HasAttribute(attr string) bool {
+func (m *Minion) HasAttribute(attr Keyword) bool {
 	_, ok := m.attributes[attr]
 	return ok
 }
@@ -368,30 +556,31 @@ func abs(x int) int {
 	}
 }
 
-func (m *Minion) InRange(u Unit) bool {
-	// If the unit has ranged it can attack
-	if m.HasAttribute("ranged") {
-		return true
+func sign(x int) int {
+	if x < 0 {
+		return -1
+	} else if x > 0 {
+		return 1
+	} else {
+		return 0
 	}
+}
 
-	pos1 := m.GetPosition()
-	pos2 := u.GetPosition()
-	absDiff := pos1.Diff(pos2).Abs()
-
-	// If the unit has blast and is orthogonal to the enemey, it can attack
-	if m.HasAttribute("blast") {
-		isInline, _ := m.IsInline(u)
-		if isInline {
-			return true
-		}
-	}
+func (m *Minion) InRange(u Unit) bool {
+	return m.GetRangeProfile().InRange(m, u)
+}
 
-	// If the unit is nearby, it can attack
-	if max(absDiff.X, absDiff.Y) == 1 {
-		return true
+// GetRangeProfile combines the unit's own profile, or melee range if it has
+// none, with those of its range keywords.
+func (m *Minion) GetRangeProfile() RangeProfile {
+	return unitRange{
+		unit:    m,
+		profile: m.rangeProfile,
 	}
+}
 
-	return false
+func (m *Minion) SetRangeProfile(profile RangeProfile) {
+	m.rangeProfile = profile
 }
 
 func (m *Minion) GetPosition() Position {
@@ -411,6 +600,10 @@ func (m *Minion) FacesRight() bool {
 	return m.faceRight
 }
 
+func (m *Minion) SetFacing(right bool) {
+	m.faceRight = right
+}
+
 func (m *Minion) GetValidMoves() map[Position]struct{} {
 	positions := map[Position]struct{}{}
 
@@ -430,11 +623,20 @@ func (m *Minion) Move(pos Position) {
 	}
 }
 
+// Place puts the unit on its owner's board, facing the way its owner does.
 func (m *Minion) Place(owner *Player, pos Position) {
 	m.owner = owner
+	m.faceRight = owner.FacesRight
 	owner.Board.PlaceUnit(m, pos)
 }
 
+// SetOwner hands the unit to another player, turning it to face the way its
+// new owner does.
+func (m *Minion) SetOwner(owner *Player) {
+	m.owner = owner
+	m.SetFacing(owner.FacesRight)
+}
+
 func (m *Minion) Remove() {
 	if m.board != nil {
 		m.board.RemoveUnit(m)
@@ -442,10 +644,53 @@ func (m *Minion) Remove() {
 	m.owner = nil
 }
 
+// A unit's turn is made of actions: an optional move followed by an optional
+// attack. Attacking ends the action, so moving after attacking needs a second
+// action (Celerity).
+func (m *Minion) IsExhausted() bool {
+	return !m.CanMove() && !m.CanAttack()
+}
+
+// Exhaust stops the unit acting until its owner's next turn.
+func (m *Minion) Exhaust() {
+	m.exhausted = true
+}
+
+func (m *Minion) Refresh() {
+	m.exhausted = false
+	m.actionsUsed = 0
+	m.moved = false
+}
+
+func (m *Minion) ActionsPerTurn() int {
+	return actionsPerTurn(m)
+}
+
+func (m *Minion) CanMove() bool {
+	return !m.exhausted && !m.moved && m.actionsUsed < m.ActionsPerTurn()
+}
+
+func (m *Minion) CanAttack() bool {
+	return !m.exhausted && m.actionsUsed < m.ActionsPerTurn()
+}
+
+func (m *Minion) SpendMove() {
+	m.moved = true
+}
+
+func (m *Minion) SpendAttack() {
+	m.actionsUsed++
+	m.moved = false
+}
+
 func (m *Minion) Dispel() {
 	m.hpDelta = 0
 	m.attackDelta = 0
-	m.attributes = map[string]int{}
+	for attr, _ := range m.attributes {
+		if isDispellable(attr) {
+			delete(m.attributes, attr)
+		}
+	}
 
 	for id, trigger := range m.triggers {
 		if trigger.CanDispel {
@@ -468,21 +713,29 @@ func (m *Minion) BuffHealth(delta int) {
 	m.hpDelta += delta
 }
 
-func (m *Minion) AddAttribute(attr string, value int) {
-	m.attributes[attr] = value
+// AddAttribute gives the unit a keyword, stacking it by the keyword's rule if
+// the unit already has it. Unknown keywords are ignored and reported as
+// ErrUnknownKeyword.
+func (m *Minion) AddAttribute(attr Keyword, value int) error {
+	return addKeyword(m.attributes, attr, value)
 }
 
-func (m *Minion) GetAttributeValue(attr string) int {
+func (m *Minion) GetAttributeValue(attr Keyword) int {
 	val, _ := m.attributes[attr]
 	return val
 }
 
-func (m *Minion) RemoveAttribute(attr string) {
+func (m *Minion) RemoveAttribute(attr Keyword) {
 	delete(m.attributes, attr)
 }
 
 func (m *Minion) SetBoard(ub *UnitBoard) {
 	m.board = ub
+
+	// A unit is given its ID the first time it enters play
+	if m.id == 0 && ub != nil {
+		m.id = ub.NewID(m)
+	}
 }
 
 func (m *Minion) GetBoard() *UnitBoard {
@@ -511,16 +764,105 @@ func (m *Minion) Unsubscribe(gs *gamestate.Gamestate) {
 	gs.RemoveInterceptor(m)
 }
 
+// Triggers and interceptors run in the order they were added so that replays
+// resolve identically.
 func (m *Minion) Notify(action gamestate.Action, gs *gamestate.Gamestate) {
-	for _, trigger := range m.triggers {
-		trigger.Trigger(action, gs)
+	for _, id := range m.triggerIDs() {
+		if trigger, ok := m.triggers[id]; ok {
+			trigger.Trigger(m, action, gs)
+		}
 	}
 }
 
 func (m *Minion) Apply(action gamestate.Action, gs *gamestate.Gamestate) gamestate.Action {
-	for _, interceptor := range m.interceptors {
-		action = interceptor.Trigger(action, gs)
+	for _, id := range m.interceptorIDs() {
+		if interceptor, ok := m.interceptors[id]; ok {
+			action = interceptor.Trigger(m, action, gs)
+		}
 	}
 
 	return action
 }
+
+func (m *Minion) triggerIDs() []int {
+	ids := []int{}
+	for id, _ := range m.triggers {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	return ids
+}
+
+func (m *Minion) interceptorIDs() []int {
+	ids := []int{}
+	for id, _ := range m.interceptors {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	return ids
+}
+
+func (m *Minion) GetCardID() CardID {
+	return m.card
+}
+
+// Copy makes a new unit with this unit's stats, buffs, keywords and triggers.
+// It is not in play and has taken no damage. Triggers are handed the copy
+// when they run, so they act for it rather than for this unit.
+func (m *Minion) Copy() Unit {
+	return &Minion{
+		name:             m.name,
+		unitType:         m.unitType,
+		subtypes:         copySubtypes(m.subtypes),
+		walkDistance:     m.walkDistance,
+		baseHp:           m.baseHp,
+		hpDelta:          m.hpDelta,
+		baseAttack:       m.baseAttack,
+		attackDelta:      m.attackDelta,
+		attributes:       copyAttributes(m.attributes),
+		rangeProfile:     m.rangeProfile,
+		triggerCount:     m.triggerCount,
+		triggers:         copyTriggers(m.triggers),
+		interceptorCount: m.interceptorCount,
+		interceptors:     copyInterceptors(m.interceptors),
+		card:             m.card,
+	}
+}
+
+func copySubtypes(subtypes map[string]struct{}) map[string]struct{} {
+	copied := map[string]struct{}{}
+	for subtype, _ := range subtypes {
+		copied[subtype] = struct{}{}
+	}
+
+	return copied
+}
+
+func copyAttributes(attributes map[Keyword]int) map[Keyword]int {
+	copied := map[Keyword]int{}
+	for attr, value := range attributes {
+		copied[attr] = value
+	}
+
+	return copied
+}
+
+func copyTriggers(triggers map[int]ActionTrigger) map[int]ActionTrigger {
+	copied := map[int]ActionTrigger{}
+	for id, trigger := range triggers {
+		copied[id] = trigger
+	}
+
+	return copied
+}
+
+func copyInterceptors(interceptors map[int]InterceptTrigger) map[int]InterceptTrigger {
+	copied := map[int]InterceptTrigger{}
+	for id, interceptor := range interceptors {
+		copied[id] = interceptor
+	}
+
+	return copied
+}
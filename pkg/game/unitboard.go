@@ -1,5 +1,11 @@
 package game
 
+import (
+	"sort"
+
+	"github.com/RGood/game_engine/pkg/gamestate"
+)
+
 type Event struct {
 	Type   string
 	Source Unit
@@ -11,6 +17,10 @@ type UnitBoard struct {
 	BoardX, BoardY int
 	Units          map[Unit]Position
 	Positions      map[Position]Unit
+	lastID         gamestate.EntityID
+	entities       map[gamestate.EntityID]interface{}
+	tiles          map[Position]*Tile
+	tileWatcher    *PhaseListener
 }
 
 type Position struct {
@@ -55,9 +65,30 @@ func NewUnitBoard(x, y int) *UnitBoard {
 		BoardY:    y,
 		Units:     map[Unit]Position{},
 		Positions: map[Position]Unit{},
+		entities:  map[gamestate.EntityID]interface{}{},
+		tiles:     map[Position]*Tile{},
 	}
 }
 
+// NewID hands out the next entity ID on this board and remembers the entity
+// it belongs to, so it can be looked up even after it leaves play. All of a
+// game's players share its board, so the board numbers the whole game.
+func (ub *UnitBoard) NewID(entity interface{}) gamestate.EntityID {
+	ub.lastID++
+	ub.entities[ub.lastID] = entity
+	return ub.lastID
+}
+
+func (ub *UnitBoard) Lookup(id gamestate.EntityID) (interface{}, bool) {
+	entity, ok := ub.entities[id]
+	return entity, ok
+}
+
+func (ub *UnitBoard) GetUnitByID(id gamestate.EntityID) Unit {
+	unit, _ := ub.entities[id].(Unit)
+	return unit
+}
+
 func (ub *UnitBoard) IsOccupied(pos Position) bool {
 	_, ok := ub.Positions[pos]
 	return ok
@@ -84,6 +115,39 @@ func (ub *UnitBoard) PlaceUnit(unit Unit, pos Position) bool {
 	return true
 }
 
+// MoveUnit moves a unit on the board to an empty tile, reporting whether it
+// could.
+func (ub *UnitBoard) MoveUnit(unit Unit, pos Position) bool {
+	from, ok := ub.Units[unit]
+	if !ok || !pos.IsOnBoard(ub) || ub.IsOccupied(pos) {
+		return false
+	}
+
+	delete(ub.Positions, from)
+	ub.Units[unit] = pos
+	ub.Positions[pos] = unit
+
+	return true
+}
+
+// SwapUnits exchanges the tiles of two units on the board in one step.
+func (ub *UnitBoard) SwapUnits(first Unit, second Unit) bool {
+	firstPos, ok := ub.Units[first]
+	if !ok {
+		return false
+	}
+
+	secondPos, ok := ub.Units[second]
+	if !ok || first == second {
+		return false
+	}
+
+	ub.Units[first], ub.Units[second] = secondPos, firstPos
+	ub.Positions[firstPos], ub.Positions[secondPos] = second, first
+
+	return true
+}
+
 func (ub *UnitBoard) RemoveUnit(unit Unit) {
 	pos := ub.Units[unit]
 	delete(ub.Units, unit)
@@ -102,10 +166,102 @@ func (ub *UnitBoard) GetPlayerUnits(owner *Player) []Unit {
 	return playerUnits
 }
 
+// SortedUnits returns every unit on the board ordered by row then column, so
+// that random picks do not depend on map iteration order.
+func (ub *UnitBoard) SortedUnits() []Unit {
+	units := []Unit{}
+	for unit, _ := range ub.Units {
+		units = append(units, unit)
+	}
+
+	sort.Slice(units, func(i, j int) bool {
+		return positionLess(ub.Units[units[i]], ub.Units[units[j]])
+	})
+
+	return units
+}
+
+func positionLess(p1, p2 Position) bool {
+	if p1.Y != p2.Y {
+		return p1.Y < p2.Y
+	}
+
+	return p1.X < p2.X
+}
+
+func (ub *UnitBoard) GetUnoccupiedTiles() []Position {
+	return ub.tilesWhere(func(pos Position) bool {
+		return !ub.IsOccupied(pos)
+	})
+}
+
+func (ub *UnitBoard) RandomUnit(rng *gamestate.RNG, filterFunc func(Unit) bool) Unit {
+	units := filterUnits(ub.SortedUnits(), filterFunc)
+	if len(units) == 0 {
+		return nil
+	}
+
+	return units[rng.Intn(len(units))]
+}
+
+func (ub *UnitBoard) RandomEnemyMinion(owner *Player, rng *gamestate.RNG) Unit {
+	return ub.RandomUnit(rng, func(unit Unit) bool {
+		return unit.GetOwner() != owner && unit.GetType() != "general"
+	})
+}
+
+func (ub *UnitBoard) RandomUnoccupiedTile(rng *gamestate.RNG) (Position, bool) {
+	tiles := ub.GetUnoccupiedTiles()
+	if len(tiles) == 0 {
+		return NewPosition(-1, -1), false
+	}
+
+	return tiles[rng.Intn(len(tiles))], true
+}
+
+// GetProvokers lists the enemy units with Provoke next to the unit. While
+// there are any, the unit cannot move and may only attack them.
+func (ub *UnitBoard) GetProvokers(unit Unit) []Unit {
+	return filterUnits(ub.SortedUnits(), func(other Unit) bool {
+		return other.IsEnemy(unit) && other.HasAttribute(Provoke) && other.IsNear(unit)
+	})
+}
+
+// IsProvoked reports whether an enemy provoker is holding the unit in place.
+func (ub *UnitBoard) IsProvoked(unit Unit) bool {
+	return len(ub.GetProvokers(unit)) > 0
+}
+
+// IsValidTarget reports whether the attacker may attack the target: an enemy
+// on this board within its range, and one of its provokers if it has any.
+// Friendly units are never valid targets.
+func (ub *UnitBoard) IsValidTarget(attacker Unit, target Unit) bool {
+	if _, ok := ub.Units[attacker]; !ok {
+		return false
+	}
+
+	if _, ok := ub.Units[target]; !ok || !attacker.IsEnemy(target) || !attacker.InRange(target) {
+		return false
+	}
+
+	provokers := ub.GetProvokers(attacker)
+	if len(provokers) == 0 {
+		return true
+	}
+
+	for _, provoker := range provokers {
+		if provoker == target {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (ub *UnitBoard) GetValidTargets(unit Unit) map[Unit]struct{} {
 	validTargets := map[Unit]struct{}{}
 	for otherUnit, _ := range ub.Units {
-		if otherUnit.GetOwner() != unit.GetOwner() && unit.InRange(otherUnit) {
+		if ub.IsValidTarget(unit, otherUnit) {
 			validTargets[otherUnit] = struct{}{}
 		}
 	}
@@ -113,8 +269,13 @@ func (ub *UnitBoard) GetValidTargets(unit Unit) map[Unit]struct{} {
 	return validTargets
 }
 
+// GetValidMoves lists the tiles the unit can walk to. It can pass through its
+// own side's units but not enemies, and cannot move at all while provoked.
 func (ub *UnitBoard) GetValidMoves(unit Unit) map[Position]struct{} {
 	validMoves := map[Position]struct{}{}
+	if ub.IsProvoked(unit) {
+		return validMoves
+	}
 
 	// Add starting tile
 	validMoves[unit.GetPosition()] = struct{}{}
@@ -1,8 +1,11 @@
 package game
 
 import (
+	"encoding/json"
+	"fmt"
 	"testing"
 
+	"github.com/RGood/game_engine/pkg/gamestate"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -40,6 +43,8 @@ func Test_validMoves(t *testing.T) {
 
 	assert.Equal(t, 12, len(p1.Board.GetValidMoves(p1general)))
 
+	gs.MakeMove(&EndTurnAction{Owner: p1})
+
 	gs.MakeMove(&MoveAction{
 		Unit:     p2general,
 		Position: NewPosition(3, 2),
@@ -50,3 +55,175 @@ func Test_validMoves(t *testing.T) {
 	// When the generals are next to each other, they are valid targets of each other
 	assert.Equal(t, map[Unit]struct{}{p2general: struct{}{}}, p1.Board.GetValidTargets(p1general))
 }
+
+func Test_seededGamesMatch(t *testing.T) {
+	playGame := func(seed int64) []string {
+		board := NewUnitBoard(9, 5)
+		p1 := NewPlayer("Foo", "Lyonar", board, NewPosition(0, 2), true)
+		p2 := NewPlayer("Bar", "Songhai", board, NewPosition(8, 2), false)
+		gs := gamestate.NewSeededGamestate(seed, p1, p2)
+
+		for i := 0; i < 6; i++ {
+			pos, ok := board.RandomUnoccupiedTile(gs.Rand())
+			assert.True(t, ok)
+			gs.MakeMove(&PlaceUnitAction{Owner: p2, Unit: NewMinion("gremlin", 2, 1), Position: pos})
+		}
+
+		for i := 0; i < 4; i++ {
+			NewGenericSpell("Random Bolt", 0, func(owner *Player, gs *gamestate.Gamestate, _ []Unit, _ []Position) {
+				if target := owner.Board.RandomEnemyMinion(owner, gs.Rand()); target != nil {
+					gs.QueueAction(&DamageAction{Unit: target, Damage: 1})
+				}
+			}).Cast(p1, gs, nil, nil)
+		}
+
+		result := []string{}
+		for _, unit := range board.SortedUnits() {
+			result = append(result, fmt.Sprintf("%s %v %d", unit.GetName(), unit.GetPosition(), unit.GetHp()))
+		}
+
+		return result
+	}
+
+	assert.Equal(t, playGame(7), playGame(7))
+	assert.NotEqual(t, playGame(7), playGame(8))
+}
+
+func Test_triggerOrder(t *testing.T) {
+	_, _, gs := setupGamestate()
+
+	order := []int{}
+	unit := NewMinion("watcher", 1, 1)
+	for i := 0; i < 20; i++ {
+		index := i
+		unit.AddActionTrigger(ActionTrigger{
+			Trigger: func(Unit, gamestate.Action, *gamestate.Gamestate) {
+				order = append(order, index)
+			},
+		})
+	}
+
+	// Triggers fire in the order they were added, not map order
+	for run := 0; run < 5; run++ {
+		order = []int{}
+		unit.Notify(&EndTurnAction{}, gs)
+		for i := range order {
+			assert.Equal(t, i, order[i])
+		}
+		assert.Len(t, order, 20)
+	}
+}
+
+func Test_entityIDs(t *testing.T) {
+	p1, p2, gs := setupGamestate()
+	p1general := p1.GetGeneral()
+
+	assert.NotEqual(t, p1.GetID(), p2.GetID())
+	assert.NotEqual(t, p1general.GetID(), p2.GetGeneral().GetID())
+
+	// Same-named tokens are different units
+	wall1 := NewWall("wall", p1, 2, 0)
+	wall2 := NewWall("wall", p1, 2, 0)
+	gs.MakeMove(&PlaceUnitAction{Owner: p1, Unit: wall1, Position: NewPosition(3, 3)})
+	endTurns(gs, p1, p2)
+	gs.MakeMove(&PlaceUnitAction{Owner: p1, Unit: wall2, Position: NewPosition(4, 4)})
+	assert.False(t, Equal(wall1, wall2))
+
+	// The ID survives moving
+	id := wall1.GetID()
+	gs.MakeMove(&MoveAction{Unit: wall1, Position: NewPosition(4, 3)})
+	assert.Equal(t, NewPosition(4, 3), wall1.GetPosition())
+	assert.Equal(t, id, wall1.GetID())
+	assert.Equal(t, wall1, p1.Board.GetUnitByID(id))
+
+	entity, ok := gs.Lookup(p2.GetID())
+	assert.True(t, ok)
+	assert.Equal(t, p2, entity)
+
+	artifact := NewArtifact("Dummy", 0)
+	artifact.Equip(p1, gs)
+	entity, ok = gs.Lookup(artifact.GetID())
+	assert.True(t, ok)
+	assert.Equal(t, artifact, entity)
+
+	_, ok = gs.Lookup(0)
+	assert.False(t, ok)
+
+	// Actions serialize their entities' state, with IDs that resolve back
+	wall2.Damage(1)
+	data, err := json.Marshal(&AttackAction{Attacker: p1general, Defender: wall2})
+	assert.Nil(t, err)
+
+	decoded := struct {
+		Attacker unitJSON
+		Defender unitJSON
+	}{}
+	assert.Nil(t, json.Unmarshal(data, &decoded))
+
+	attacker, _ := gs.Lookup(decoded.Attacker.ID)
+	defender, _ := gs.Lookup(decoded.Defender.ID)
+	assert.Equal(t, p1general, attacker)
+	assert.Equal(t, wall2, defender)
+
+	pos := NewPosition(4, 4)
+	assert.Equal(t, unitJSON{
+		ID:         wall2.GetID(),
+		Name:       "wall",
+		Type:       "token",
+		Subtypes:   []string{"wall"},
+		Owner:      p1.GetID(),
+		Position:   &pos,
+		FacesRight: true,
+		Hp:         1,
+		Damage:     1,
+		Exhausted:  true,
+		Keywords:   map[Keyword]int{},
+	}, decoded.Defender)
+}
+
+func Test_cardIDsInHand(t *testing.T) {
+	p1, _, gs := setupGamestate()
+	p1.Mana = 9
+
+	bolt := NewGenericSpell("Bolt", 1, func(*Player, *gamestate.Gamestate, []Unit, []Position) {})
+	artifact := NewArtifact("Dummy", 2)
+	minion := NewUnitFactory().SetName("Imp").SetHealth(1).SetAttack(1).SetCost(1)
+
+	// Cards take their ID as they are drawn, before they are played
+	p1.AddToHand(bolt)
+	p1.AddToHand(artifact)
+	p1.AddToHand(minion)
+	assert.NotZero(t, bolt.GetID())
+	assert.NotZero(t, artifact.GetID())
+	assert.NotEqual(t, bolt.GetID(), artifact.GetID())
+
+	entity, ok := gs.Lookup(bolt.GetID())
+	assert.True(t, ok)
+	assert.Equal(t, bolt, entity)
+
+	id := bolt.GetID()
+	assert.Nil(t, bolt.Cast(p1, gs, nil, nil))
+	assert.Equal(t, id, bolt.GetID())
+	assert.Nil(t, artifact.Play(p1, gs))
+	assert.Equal(t, []Card{minion}, p1.Hand())
+
+	data, err := json.Marshal(p1)
+	assert.Nil(t, err)
+
+	decoded := struct {
+		ID        gamestate.EntityID
+		Mana      int
+		Artifacts []artifactJSON
+	}{}
+	assert.Nil(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, p1.GetID(), decoded.ID)
+	assert.Equal(t, 6, decoded.Mana)
+	assert.Equal(t, []artifactJSON{{
+		ID:        artifact.GetID(),
+		Name:      "Dummy",
+		Cost:      2,
+		Charges:   3,
+		CanDispel: true,
+		Owner:     p1.GetID(),
+	}}, decoded.Artifacts)
+}
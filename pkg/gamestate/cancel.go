@@ -0,0 +1,40 @@
+package gamestate
+
+// CancelledAction is returned by an interceptor in place of an action it has
+// countered. No further interceptors see it and the original action is never
+// executed; listeners are notified with the CancelledAction instead so they
+// can tell what was cancelled and by whom.
+type CancelledAction struct {
+	Action Action
+	By     Interceptor
+	Refund bool
+}
+
+// Refundable actions are told when they are cancelled with a refund, so they
+// can give back whatever was paid to make them.
+type Refundable interface {
+	Refund(*Gamestate)
+}
+
+func Cancel(action Action, by Interceptor) *CancelledAction {
+	return &CancelledAction{
+		Action: action,
+		By:     by,
+	}
+}
+
+func CancelWithRefund(action Action, by Interceptor) *CancelledAction {
+	return &CancelledAction{
+		Action: action,
+		By:     by,
+		Refund: true,
+	}
+}
+
+func (ca *CancelledAction) Execute(gs *Gamestate) *Gamestate {
+	if refundable, ok := ca.Action.(Refundable); ok && ca.Refund {
+		refundable.Refund(gs)
+	}
+
+	return gs
+}
@@ -0,0 +1,32 @@
+package gamestate
+
+// EntityID identifies a unit, player, artifact or card for the whole game.
+// IDs are only unique within one game: each game hands out its own, starting
+// from one. Zero means no ID has been assigned yet.
+type EntityID uint64
+
+// Directory resolves IDs it handed out back to their entities. Players that
+// implement Directory are added automatically by NewGamestate.
+type Directory interface {
+	Lookup(EntityID) (interface{}, bool)
+}
+
+func (gs *Gamestate) AddDirectory(directory Directory) {
+	for _, existing := range gs.directories {
+		if existing == directory {
+			return
+		}
+	}
+
+	gs.directories = append(gs.directories, directory)
+}
+
+func (gs *Gamestate) Lookup(id EntityID) (interface{}, bool) {
+	for _, directory := range gs.directories {
+		if entity, ok := directory.Lookup(id); ok {
+			return entity, true
+		}
+	}
+
+	return nil, false
+}
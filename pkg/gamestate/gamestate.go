@@ -3,70 +3,335 @@ package gamestate
 type Gamestate struct {
 	Players      []Player
 	ActivePlayer Player
-	actions      []Action
+	actions      Resolver
 
-	listeners    map[Listener]struct{}
-	interceptors map[Interceptor]struct{}
+	// Listeners and interceptors are kept in subscription order so that a
+	// replay with the same seed triggers them (and draws from rng) identically.
+	listeners    []Listener
+	interceptors []Interceptor
+
+	seed int64
+	rng  *RNG
+
+	prompt *Prompt
+
+	limits   Limits
+	current  *QueuedAction
+	resolved int
+	err      error
+
+	log []*QueuedAction
+
+	directories []Directory
+	stateful    []Stateful
 
 	ended bool
 }
 
+// Snapshot holds the engine-level state needed to resume a game exactly.
+type Snapshot struct {
+	ActivePlayer int
+	Seed         int64
+	RandomState  uint64
+	States       []interface{}
+}
+
 func NewGamestate(players ...Player) *Gamestate {
+	return NewSeededGamestate(0, players...)
+}
+
+func NewSeededGamestate(seed int64, players ...Player) *Gamestate {
 	gs := &Gamestate{
 		Players:      players,
 		ActivePlayer: players[0],
-		actions:      []Action{},
+		actions:      NewFIFOResolver(),
 		ended:        false,
-		listeners:    map[Listener]struct{}{},
-		interceptors: map[Interceptor]struct{}{},
+		listeners:    []Listener{},
+		interceptors: []Interceptor{},
+		seed:         seed,
+		rng:          NewRNG(seed),
+		limits:       DefaultLimits(),
+		log:          []*QueuedAction{},
+		directories:  []Directory{},
+		stateful:     []Stateful{},
+	}
+
+	for _, player := range players {
+		if directory, ok := player.(Directory); ok {
+			gs.AddDirectory(directory)
+		}
+
+		if stateful, ok := player.(Stateful); ok {
+			gs.AddStateful(stateful)
+		}
 	}
 
 	return gs
 }
 
+func (gs *Gamestate) Seed() int64 {
+	return gs.seed
+}
+
+// Rand is the only source of randomness cards should use.
+func (gs *Gamestate) Rand() *RNG {
+	return gs.rng
+}
+
+func (gs *Gamestate) Snapshot() Snapshot {
+	active := 0
+	for index, player := range gs.Players {
+		if player == gs.ActivePlayer {
+			active = index
+			break
+		}
+	}
+
+	states := []interface{}{}
+	for _, stateful := range gs.stateful {
+		states = append(states, stateful.SaveState())
+	}
+
+	return Snapshot{
+		ActivePlayer: active,
+		Seed:         gs.seed,
+		RandomState:  gs.rng.State(),
+		States:       states,
+	}
+}
+
+func (gs *Gamestate) Restore(snapshot Snapshot) {
+	gs.ActivePlayer = gs.Players[snapshot.ActivePlayer]
+	gs.seed = snapshot.Seed
+	gs.rng.SetState(snapshot.RandomState)
+
+	for index, stateful := range gs.stateful {
+		if index < len(snapshot.States) {
+			stateful.LoadState(snapshot.States[index])
+		}
+	}
+}
+
+func (gs *Gamestate) isSubscribed(l Listener) bool {
+	for _, listener := range gs.listeners {
+		if listener == l {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (gs *Gamestate) hasInterceptor(i Interceptor) bool {
+	for _, interceptor := range gs.interceptors {
+		if interceptor == i {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Listeners may (un)subscribe while being notified, so notification walks a
+// copy and skips anything removed part way through.
+func (gs *Gamestate) currentListeners() []Listener {
+	return append([]Listener{}, gs.listeners...)
+}
+
 func (gs *Gamestate) Subscribe(l Listener) {
-	gs.listeners[l] = struct{}{}
+	if !gs.isSubscribed(l) {
+		gs.listeners = append(gs.listeners, l)
+	}
 }
 
 func (gs *Gamestate) Unsubscribe(l Listener) {
-	delete(gs.listeners, l)
+	for index, listener := range gs.listeners {
+		if listener == l {
+			gs.listeners = append(gs.listeners[:index:index], gs.listeners[index+1:]...)
+			return
+		}
+	}
 }
 
 func (gs *Gamestate) AddInterceptor(i Interceptor) {
-	gs.interceptors[i] = struct{}{}
+	if !gs.hasInterceptor(i) {
+		gs.interceptors = append(gs.interceptors, i)
+	}
 }
 
 func (gs *Gamestate) RemoveInterceptor(i Interceptor) {
-	delete(gs.interceptors, i)
+	for index, interceptor := range gs.interceptors {
+		if interceptor == i {
+			gs.interceptors = append(gs.interceptors[:index:index], gs.interceptors[index+1:]...)
+			return
+		}
+	}
+}
+
+// SetResolver changes the resolution strategy. Pending actions are moved to
+// the new resolver in the order the old one would have resolved them.
+func (gs *Gamestate) SetResolver(resolver Resolver) {
+	for queued, ok := gs.actions.Pop(); ok; queued, ok = gs.actions.Pop() {
+		resolver.Push(queued)
+	}
+
+	gs.actions = resolver
 }
 
+// QueueAction queues an action triggered by the one currently resolving.
 func (gs *Gamestate) QueueAction(action Action) {
-	gs.actions = append(gs.actions, action)
+	gs.actions.Push(gs.childOf(gs.current, action))
 }
 
+func (gs *Gamestate) childOf(parent *QueuedAction, action Action) *QueuedAction {
+	queued := &QueuedAction{
+		Action: action,
+	}
+
+	if parent != nil {
+		queued.Parent = parent
+		queued.Root = parent.Root
+		queued.Depth = parent.Depth + 1
+	} else {
+		queued.Root = queued
+	}
+
+	return queued
+}
+
+// ResolveNow resolves an action immediately, as a child of the one currently
+// resolving, rather than queueing it. Interceptors and listeners see it as
+// usual. It returns the action as executed (possibly replaced or cancelled by
+// an interceptor), or nil if a limit stopped the chain.
+func (gs *Gamestate) ResolveNow(action Action) Action {
+	outer := gs.current
+	queued := gs.childOf(outer, action)
+
+	gs.resolved++
+	if err := gs.checkLimits(queued); err != nil {
+		gs.halt(err)
+		return nil
+	}
+
+	gs.step(queued)
+	gs.current = outer
+
+	return queued.Action
+}
+
+// Current is the action resolving right now, with its causal chain. It is nil
+// outside of resolution.
+func (gs *Gamestate) Current() *QueuedAction {
+	return gs.current
+}
+
+// Log lists the actions resolved by the latest move, in resolution order,
+// with the action as it was executed after interception. It is cleared as
+// each new move starts so that a long game doesn't keep its whole history.
+func (gs *Gamestate) Log() []*QueuedAction {
+	return gs.log
+}
+
+// MakeMove queues a player command and resolves the queue. Moves are ignored
+// while the game is over or a prompt is waiting for an answer.
 func (gs *Gamestate) MakeMove(action Action) *Gamestate {
-	if gs.HasEnded() {
+	if gs.HasEnded() || gs.prompt != nil {
 		return gs
 	}
 
-	gs.actions = append(gs.actions, action)
-	for len(gs.actions) > 0 {
-		activeMove := gs.actions[0]
-		gs.actions = gs.actions[1:]
+	if gs.current == nil {
+		gs.resetLimits()
+		gs.log = []*QueuedAction{}
+	}
+
+	gs.QueueAction(action)
+	return gs.resolve()
+}
+
+func (gs *Gamestate) resetLimits() {
+	gs.resolved = 0
+	gs.err = nil
+}
+
+func (gs *Gamestate) resolve() *Gamestate {
+	// Moves made from inside a trigger resolve nested, so put back whatever
+	// was resolving when we return.
+	outer := gs.current
+	defer func() {
+		gs.current = outer
+	}()
 
-		for i, _ := range gs.interceptors {
-			activeMove = i.Apply(activeMove, gs)
+	for gs.drain() && outer == nil {
+		// Settling may queue more actions (an aura ending can kill a unit),
+		// which form part of the same chain.
+		gs.current = nil
+		gs.settle()
+		if gs.actions.Len() == 0 {
+			break
 		}
+	}
 
-		activeMove.Execute(gs)
+	return gs
+}
 
-		for listener, _ := range gs.listeners {
-			listener.Notify(activeMove, gs)
+// drain resolves queued actions until the queue is empty, a prompt is
+// pending or a limit is hit. It reports whether the queue was emptied.
+func (gs *Gamestate) drain() bool {
+	for gs.actions.Len() > 0 && gs.prompt == nil {
+		queued, _ := gs.actions.Pop()
+		gs.resolved++
+		if err := gs.checkLimits(queued); err != nil {
+			gs.halt(err)
+			return false
 		}
 
+		gs.step(queued)
 	}
 
-	return gs
+	return gs.prompt == nil
+}
+
+func (gs *Gamestate) step(queued *QueuedAction) {
+	gs.current = queued
+	activeMove := queued.Action
+
+	for _, i := range gs.interceptors {
+		activeMove = i.Apply(activeMove, gs)
+
+		if cancelled, ok := activeMove.(*CancelledAction); ok {
+			if cancelled.By == nil {
+				cancelled.By = i
+			}
+			break
+		}
+	}
+
+	queued.Action = activeMove
+	gs.log = append(gs.log, queued)
+
+	if _, cancelled := activeMove.(*CancelledAction); !cancelled {
+		for _, listener := range gs.currentListeners() {
+			if before, ok := listener.(BeforeListener); ok && gs.isSubscribed(listener) {
+				before.BeforeExecute(activeMove, gs)
+			}
+		}
+	}
+
+	activeMove.Execute(gs)
+
+	for _, listener := range gs.currentListeners() {
+		if gs.isSubscribed(listener) {
+			listener.Notify(activeMove, gs)
+		}
+	}
+}
+
+func (gs *Gamestate) settle() {
+	for _, listener := range gs.currentListeners() {
+		if settler, ok := listener.(SettleListener); ok && gs.isSubscribed(listener) {
+			settler.Settle(gs)
+		}
+	}
 }
 
 func (gs *Gamestate) EndTurn() *Gamestate {
@@ -95,6 +360,10 @@ func (gs *Gamestate) EndTurn() *Gamestate {
 }
 
 func (gs *Gamestate) Winner() (bool, Player) {
+	if gs.IsDraw() {
+		return true, nil
+	}
+
 	if gs.HasEnded() {
 		var winner Player
 		for _, player := range gs.Players {
@@ -119,5 +388,10 @@ func (gs *Gamestate) HasEnded() bool {
 		}
 	}
 
-	return livePlayerCount < 2
+	return gs.ended || livePlayerCount < 2
+}
+
+// IsDraw is true when the game was ended without a winner.
+func (gs *Gamestate) IsDraw() bool {
+	return gs.ended
 }
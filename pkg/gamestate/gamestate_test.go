@@ -49,3 +49,232 @@ func Test_gamestate(t *testing.T) {
 	gamestate.EndTurn()
 	assert.Equal(t, p2, gamestate.ActivePlayer)
 }
+
+func Test_seededRandomness(t *testing.T) {
+	gs1 := NewSeededGamestate(42, NewTestPlayer(true), NewTestPlayer(true))
+	gs2 := NewSeededGamestate(42, NewTestPlayer(true), NewTestPlayer(true))
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, gs1.Rand().Intn(100), gs2.Rand().Intn(100))
+	}
+
+	snapshot := gs1.Snapshot()
+	first := []int{gs1.Rand().Intn(100), gs1.Rand().Intn(100), gs1.Rand().Intn(100)}
+
+	gs1.Restore(snapshot)
+	assert.Equal(t, first, []int{gs1.Rand().Intn(100), gs1.Rand().Intn(100), gs1.Rand().Intn(100)})
+
+	// Clones keep the same sequence without sharing state
+	clone := gs1.Rand().Clone()
+	assert.Equal(t, gs1.Rand().Intn(100), clone.Intn(100))
+	clone.Intn(100)
+	assert.NotEqual(t, gs1.Rand().State(), clone.State())
+}
+
+type counterState struct {
+	count int
+}
+
+func (cs *counterState) SaveState() interface{} {
+	return cs.count
+}
+
+func (cs *counterState) LoadState(state interface{}) {
+	cs.count = state.(int)
+}
+
+func Test_statefulSnapshot(t *testing.T) {
+	gs := NewGamestate(NewTestPlayer(true), NewTestPlayer(true))
+	counter := &counterState{count: 1}
+	gs.AddStateful(counter)
+	gs.AddStateful(counter)
+
+	snapshot := gs.Snapshot()
+	assert.Len(t, snapshot.States, 1)
+
+	counter.count = 5
+	gs.Restore(snapshot)
+	assert.Equal(t, 1, counter.count)
+}
+
+type recordAction struct {
+	name string
+	log  *[]string
+}
+
+func (ra *recordAction) Execute(gs *Gamestate) *Gamestate {
+	*ra.log = append(*ra.log, ra.name)
+	return gs
+}
+
+type promptAction struct {
+	prompt *Prompt
+}
+
+func (pa *promptAction) Execute(gs *Gamestate) *Gamestate {
+	gs.RequestChoice(pa.prompt)
+	return gs
+}
+
+func Test_promptSuspendsResolution(t *testing.T) {
+	p1 := NewTestPlayer(true)
+	p2 := NewTestPlayer(true)
+	gs := NewGamestate(p1, p2)
+
+	log := []string{}
+	gs.QueueAction(&promptAction{prompt: &Prompt{
+		Player:  p1,
+		Options: []string{"left", "right"},
+		Default: 1,
+		OnAnswer: func(choice int, gs *Gamestate) {
+			gs.QueueAction(&recordAction{name: []string{"left", "right"}[choice], log: &log})
+		},
+	}})
+	gs.QueueAction(&recordAction{name: "sibling", log: &log})
+	gs.MakeMove(&recordAction{name: "move", log: &log})
+
+	assert.NotNil(t, gs.PendingPrompt())
+	assert.Equal(t, []string{}, log)
+
+	// Other moves are refused while waiting
+	gs.MakeMove(&recordAction{name: "ignored", log: &log})
+	assert.Equal(t, []string{}, log)
+
+	assert.Equal(t, ErrWrongPlayer, gs.AnswerPrompt(p2, 0))
+	assert.Equal(t, ErrInvalidChoice, gs.AnswerPrompt(p1, 2))
+	assert.Nil(t, gs.AnswerPrompt(p1, 0))
+
+	assert.Nil(t, gs.PendingPrompt())
+	assert.Equal(t, []string{"sibling", "move", "left"}, log)
+	assert.Equal(t, ErrNoPendingPrompt, gs.AnswerPrompt(p1, 0))
+}
+
+func Test_promptTimeout(t *testing.T) {
+	p1 := NewTestPlayer(true)
+	gs := NewGamestate(p1, NewTestPlayer(true))
+
+	chosen := -1
+	gs.MakeMove(&promptAction{prompt: &Prompt{
+		Player:  p1,
+		Options: []string{"a", "b", "c"},
+		Default: 2,
+		OnAnswer: func(choice int, _ *Gamestate) {
+			chosen = choice
+		},
+	}})
+
+	assert.Nil(t, gs.TimeoutPrompt())
+	assert.Equal(t, 2, chosen)
+}
+
+func Test_promptValidation(t *testing.T) {
+	p1 := NewTestPlayer(true)
+	gs := NewGamestate(p1, NewTestPlayer(true))
+
+	assert.ErrorIs(t, gs.RequestChoice(&Prompt{Player: p1}), ErrNoOptions)
+	assert.ErrorIs(t, gs.RequestChoice(&Prompt{Player: p1, Options: []string{"a", "b"}, Default: 2}), ErrInvalidDefault)
+	assert.ErrorIs(t, gs.RequestChoice(&Prompt{Player: p1, Options: []string{"a", "b"}, Default: -1}), ErrInvalidDefault)
+	assert.Nil(t, gs.PendingPrompt())
+
+	assert.NoError(t, gs.RequestChoice(&Prompt{Player: p1, Options: []string{"a"}}))
+	assert.ErrorIs(t, gs.RequestChoice(&Prompt{Player: p1, Options: []string{"b"}}), ErrPromptPending)
+}
+
+type pingAction struct{}
+
+func (pa *pingAction) Execute(gs *Gamestate) *Gamestate {
+	return gs
+}
+
+type pongAction struct{}
+
+func (pa *pongAction) Execute(gs *Gamestate) *Gamestate {
+	return gs
+}
+
+// echoListener queues a fresh reply every time it sees its trigger
+type echoListener struct {
+	matches func(Action) bool
+	reply   func() Action
+	replies int
+}
+
+func (el *echoListener) Notify(action Action, gs *Gamestate) {
+	if el.matches(action) {
+		for i := 0; i < el.replies; i++ {
+			gs.QueueAction(el.reply())
+		}
+	}
+}
+
+func (el *echoListener) Subscribe(gs *Gamestate) {
+	gs.Subscribe(el)
+}
+
+func (el *echoListener) Unsubscribe(gs *Gamestate) {
+	gs.Unsubscribe(el)
+}
+
+func subscribePingPong(gs *Gamestate, replies int) {
+	(&echoListener{
+		matches: func(a Action) bool { _, ok := a.(*pingAction); return ok },
+		reply:   func() Action { return &pongAction{} },
+		replies: replies,
+	}).Subscribe(gs)
+	(&echoListener{
+		matches: func(a Action) bool { _, ok := a.(*pongAction); return ok },
+		reply:   func() Action { return &pingAction{} },
+		replies: replies,
+	}).Subscribe(gs)
+}
+
+func Test_triggerLoopAborts(t *testing.T) {
+	p1 := NewTestPlayer(true)
+	gs := NewGamestate(p1, NewTestPlayer(true))
+	subscribePingPong(gs, 1)
+
+	gs.MakeMove(&pingAction{})
+
+	assert.Equal(t, ErrTriggerLoop, gs.Err())
+	assert.False(t, gs.HasEnded())
+
+	// The next move starts with a clean slate
+	log := []string{}
+	gs.MakeMove(&recordAction{name: "after", log: &log})
+	assert.Nil(t, gs.Err())
+	assert.Equal(t, []string{"after"}, log)
+}
+
+func Test_actionLimitDraws(t *testing.T) {
+	gs := NewGamestate(NewTestPlayer(true), NewTestPlayer(true))
+	gs.SetLimits(Limits{
+		MaxActions: 50,
+		MaxDepth:   0,
+		OnExceeded: Draw,
+	})
+	subscribePingPong(gs, 2)
+
+	gs.MakeMove(&pingAction{})
+
+	assert.Equal(t, ErrActionLimit, gs.Err())
+	assert.True(t, gs.HasEnded())
+	assert.True(t, gs.IsDraw())
+
+	hasEnded, winner := gs.Winner()
+	assert.True(t, hasEnded)
+	assert.Nil(t, winner)
+}
+
+func Test_logIsPerMove(t *testing.T) {
+	gs := NewGamestate(NewTestPlayer(true), NewTestPlayer(true))
+
+	log := []string{}
+	for i := 0; i < 100; i++ {
+		gs.MakeMove(&recordAction{name: "move", log: &log})
+		gs.QueueAction(&recordAction{name: "child", log: &log})
+		gs.MakeMove(&recordAction{name: "move", log: &log})
+	}
+
+	assert.Len(t, log, 300)
+	assert.Len(t, gs.Log(), 2)
+}
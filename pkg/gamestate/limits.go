@@ -0,0 +1,69 @@
+package gamestate
+
+import "errors"
+
+var (
+	ErrActionLimit = errors.New("too many actions resolved for a single move")
+	ErrTriggerLoop = errors.New("triggered actions nested too deeply, likely a trigger loop")
+)
+
+type LimitOutcome int
+
+const (
+	// AbortChain drops every pending action and leaves the game running.
+	AbortChain LimitOutcome = iota
+	// Draw drops every pending action and ends the game with no winner.
+	Draw
+)
+
+// Limits bound how much work a single move may cause. A zero maximum means
+// no limit.
+type Limits struct {
+	MaxActions int
+	MaxDepth   int
+	OnExceeded LimitOutcome
+}
+
+func DefaultLimits() Limits {
+	return Limits{
+		MaxActions: 1000,
+		MaxDepth:   100,
+		OnExceeded: AbortChain,
+	}
+}
+
+func (gs *Gamestate) SetLimits(limits Limits) {
+	gs.limits = limits
+}
+
+func (gs *Gamestate) GetLimits() Limits {
+	return gs.limits
+}
+
+// Err reports why the last move's chain was stopped, or nil if it resolved
+// normally.
+func (gs *Gamestate) Err() error {
+	return gs.err
+}
+
+func (gs *Gamestate) checkLimits(queued *QueuedAction) error {
+	if gs.limits.MaxActions > 0 && gs.resolved > gs.limits.MaxActions {
+		return ErrActionLimit
+	}
+
+	if gs.limits.MaxDepth > 0 && queued.Depth > gs.limits.MaxDepth {
+		return ErrTriggerLoop
+	}
+
+	return nil
+}
+
+func (gs *Gamestate) halt(err error) {
+	for _, ok := gs.actions.Pop(); ok; _, ok = gs.actions.Pop() {
+	}
+
+	gs.err = err
+	if gs.limits.OnExceeded == Draw {
+		gs.ended = true
+	}
+}
@@ -5,3 +5,17 @@ type Listener interface {
 	Subscribe(*Gamestate)
 	Unsubscribe(*Gamestate)
 }
+
+// BeforeListener is an optional extension of Listener. BeforeExecute is called
+// once interceptors have run but before the action executes; Notify remains
+// the after-execution hook. Cancelled actions never reach BeforeExecute.
+type BeforeListener interface {
+	BeforeExecute(Action, *Gamestate)
+}
+
+// SettleListener is an optional extension of Listener. Settle is called each
+// time a move's chain has fully resolved; anything it queues resolves as part
+// of the same chain and is followed by another Settle.
+type SettleListener interface {
+	Settle(*Gamestate)
+}
@@ -0,0 +1,96 @@
+package gamestate
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrNoPendingPrompt = errors.New("no prompt is pending")
+	ErrWrongPlayer     = errors.New("prompt belongs to another player")
+	ErrInvalidChoice   = errors.New("choice is not one of the prompt options")
+	ErrPromptPending   = errors.New("another prompt is already pending")
+	ErrNoOptions       = errors.New("prompt has no options")
+	ErrInvalidDefault  = errors.New("prompt default is not one of its options")
+)
+
+// Prompt asks a player to pick one of Options while an action is resolving.
+// Resolution of the action queue is suspended until the prompt is answered.
+// Timeout is advisory: the engine has no clock, so whoever drives the game
+// calls TimeoutPrompt once it elapses and Default is chosen.
+type Prompt struct {
+	Player   Player
+	Options  []string
+	Default  int
+	Timeout  time.Duration
+	OnAnswer func(int, *Gamestate)
+
+	source *QueuedAction
+}
+
+// RequestChoice suspends resolution after the current action until the
+// prompt is answered. Only one prompt may be pending at a time, and Default
+// must be one of the options.
+func (gs *Gamestate) RequestChoice(prompt *Prompt) error {
+	if gs.prompt != nil {
+		return ErrPromptPending
+	}
+
+	if len(prompt.Options) == 0 {
+		return ErrNoOptions
+	}
+
+	if prompt.Default < 0 || prompt.Default >= len(prompt.Options) {
+		return ErrInvalidDefault
+	}
+
+	prompt.source = gs.current
+	gs.prompt = prompt
+	return nil
+}
+
+func (gs *Gamestate) PendingPrompt() *Prompt {
+	return gs.prompt
+}
+
+func (gs *Gamestate) AnswerPrompt(player Player, choice int) error {
+	if gs.prompt == nil {
+		return ErrNoPendingPrompt
+	}
+
+	if gs.prompt.Player != player {
+		return ErrWrongPlayer
+	}
+
+	if choice < 0 || choice >= len(gs.prompt.Options) {
+		return ErrInvalidChoice
+	}
+
+	gs.answer(choice)
+	return nil
+}
+
+func (gs *Gamestate) TimeoutPrompt() error {
+	if gs.prompt == nil {
+		return ErrNoPendingPrompt
+	}
+
+	gs.answer(gs.prompt.Default)
+	return nil
+}
+
+func (gs *Gamestate) answer(choice int) {
+	prompt := gs.prompt
+	gs.prompt = nil
+	gs.resetLimits()
+
+	// Whatever the answer queues belongs to the action that asked
+	outer := gs.current
+	gs.current = prompt.source
+	if prompt.OnAnswer != nil {
+		prompt.OnAnswer(choice, gs)
+	}
+	gs.current = outer
+
+	gs.resolve()
+}
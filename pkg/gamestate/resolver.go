@@ -0,0 +1,145 @@
+package gamestate
+
+// QueuedAction is an action waiting in a Resolver. Parent is the action that
+// was resolving when it was queued and Root the player command that started
+// the chain (a root is its own Root). Depth is 0 for roots and one more than
+// the parent for anything triggered.
+type QueuedAction struct {
+	Action Action
+	Parent *QueuedAction
+	Root   *QueuedAction
+	Depth  int
+}
+
+func (qa *QueuedAction) IsRoot() bool {
+	return qa.Parent == nil
+}
+
+// Ancestor walks up the chain from the parent and returns the closest action
+// matching the filter.
+func (qa *QueuedAction) Ancestor(match func(Action) bool) (Action, bool) {
+	for parent := qa.Parent; parent != nil; parent = parent.Parent {
+		if match(parent.Action) {
+			return parent.Action, true
+		}
+	}
+
+	return nil, false
+}
+
+// Resolver owns the pending action queue and decides which action resolves
+// next. Anything pushed after a Pop was queued while that action resolved.
+type Resolver interface {
+	Push(*QueuedAction)
+	Pop() (*QueuedAction, bool)
+	Len() int
+}
+
+// FIFOResolver resolves actions in the order they were queued, so triggered
+// actions wait behind every sibling queued before them.
+type FIFOResolver struct {
+	actions []*QueuedAction
+}
+
+func NewFIFOResolver() *FIFOResolver {
+	return &FIFOResolver{
+		actions: []*QueuedAction{},
+	}
+}
+
+func (r *FIFOResolver) Push(action *QueuedAction) {
+	r.actions = append(r.actions, action)
+}
+
+func (r *FIFOResolver) Pop() (*QueuedAction, bool) {
+	if len(r.actions) == 0 {
+		return nil, false
+	}
+
+	action := r.actions[0]
+	r.actions = r.actions[1:]
+	return action, true
+}
+
+func (r *FIFOResolver) Len() int {
+	return len(r.actions)
+}
+
+// LIFOResolver resolves the most recently queued action first.
+type LIFOResolver struct {
+	actions []*QueuedAction
+}
+
+func NewLIFOResolver() *LIFOResolver {
+	return &LIFOResolver{
+		actions: []*QueuedAction{},
+	}
+}
+
+func (r *LIFOResolver) Push(action *QueuedAction) {
+	r.actions = append(r.actions, action)
+}
+
+func (r *LIFOResolver) Pop() (*QueuedAction, bool) {
+	if len(r.actions) == 0 {
+		return nil, false
+	}
+
+	action := r.actions[len(r.actions)-1]
+	r.actions = r.actions[:len(r.actions)-1]
+	return action, true
+}
+
+func (r *LIFOResolver) Len() int {
+	return len(r.actions)
+}
+
+// DepthFirstResolver gives every resolving action its own child queue. The
+// children resolve in the order they were queued, and all of them (and their
+// own children) resolve before the next sibling of their parent.
+type DepthFirstResolver struct {
+	queues [][]*QueuedAction
+}
+
+func NewDepthFirstResolver() *DepthFirstResolver {
+	return &DepthFirstResolver{
+		queues: [][]*QueuedAction{},
+	}
+}
+
+func (r *DepthFirstResolver) Push(action *QueuedAction) {
+	if len(r.queues) == 0 {
+		r.queues = append(r.queues, []*QueuedAction{})
+	}
+
+	top := len(r.queues) - 1
+	r.queues[top] = append(r.queues[top], action)
+}
+
+func (r *DepthFirstResolver) Pop() (*QueuedAction, bool) {
+	for len(r.queues) > 0 && len(r.queues[len(r.queues)-1]) == 0 {
+		r.queues = r.queues[:len(r.queues)-1]
+	}
+
+	if len(r.queues) == 0 {
+		return nil, false
+	}
+
+	top := len(r.queues) - 1
+	action := r.queues[top][0]
+	r.queues[top] = r.queues[top][1:]
+
+	// Start the child queue for the action about to resolve
+	r.queues = append(r.queues, []*QueuedAction{})
+
+	return action, true
+}
+
+func (r *DepthFirstResolver) Len() int {
+	count := 0
+	for _, queue := range r.queues {
+		count += len(queue)
+	}
+
+	return count
+}
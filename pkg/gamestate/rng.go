@@ -0,0 +1,52 @@
+package gamestate
+
+// RNG is a splitmix64 generator. Its entire state is a single word so it can be
+// captured in a Snapshot and restored to replay a game exactly.
+type RNG struct {
+	state uint64
+}
+
+func NewRNG(seed int64) *RNG {
+	return &RNG{
+		state: uint64(seed),
+	}
+}
+
+func (r *RNG) State() uint64 {
+	return r.state
+}
+
+func (r *RNG) SetState(state uint64) {
+	r.state = state
+}
+
+// Clone returns an independent generator that continues the same sequence,
+// so a cloned game draws the same random numbers as the original.
+func (r *RNG) Clone() *RNG {
+	return &RNG{
+		state: r.state,
+	}
+}
+
+func (r *RNG) Uint64() uint64 {
+	r.state += 0x9e3779b97f4a7c15
+	z := r.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// Intn returns a value in [0, n). It returns 0 when n <= 0.
+func (r *RNG) Intn(n int) int {
+	if n <= 0 {
+		return 0
+	}
+
+	return int(r.Uint64() % uint64(n))
+}
+
+func (r *RNG) Shuffle(n int, swap func(i, j int)) {
+	for i := n - 1; i > 0; i-- {
+		swap(i, r.Intn(i+1))
+	}
+}
@@ -0,0 +1,20 @@
+package gamestate
+
+// Stateful is game state kept outside the engine, such as a player's board.
+// Snapshot saves every Stateful in the order it was added and Restore loads it
+// back. Players that implement Stateful are added automatically by
+// NewGamestate.
+type Stateful interface {
+	SaveState() interface{}
+	LoadState(interface{})
+}
+
+func (gs *Gamestate) AddStateful(stateful Stateful) {
+	for _, existing := range gs.stateful {
+		if existing == stateful {
+			return
+		}
+	}
+
+	gs.stateful = append(gs.stateful, stateful)
+}